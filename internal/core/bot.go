@@ -5,50 +5,137 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"sync"
 	"time"
 
+	"github.com/artemlive/tars/pkg/calendar"
+	"github.com/artemlive/tars/pkg/oncall"
 	slackx "github.com/artemlive/tars/pkg/slack"
 	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/tracker"
 	"github.com/artemlive/tars/pkg/utils"
+	"github.com/artemlive/tars/pkg/webhook"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 	charts "github.com/vicanso/go-charts/v2"
 )
 
+// Metrics the stats charts can plot.
+const (
+	statsMetricRequests  = "requests"  // distinct requests per category
+	statsMetricReactions = "reactions" // categorized reactions per category
+)
+
 // Bot represents the TARS bot.
 type Bot struct {
-	slackClient slackx.Client
-	config      *utils.Config
-	ctx         context.Context
-	repo        storage.StatsRepository
+	slackClient    slackx.Client
+	config         *utils.Config
+	ctx            context.Context
+	stop           context.CancelFunc // cancels ctx, which stops the background loops
+	repo           storage.StatsRepository
+	statsProcessor *StatsProcessor
+	requestsMu     sync.Mutex                 // serializes request and stats updates
+	businessHours  *calendar.Calendar         // nil if business hours aren't configured
+	onCall         map[string]oncall.Schedule // channelID -> on-call schedule
+	issueTracker   tracker.IssueTracker       // nil if no issue tracker is configured
+	webhooks       *webhook.Dispatcher        // nil if no webhooks are configured
+	pendingTickets map[string]bool            // requests a ticket is being created for, guarded by requestsMu
+	botUserID      string                     // user the bot reacts as, its reactions don't count
 }
 
 // NewBot initializes the bot with its dependencies.
 func NewBot(ctx context.Context, client slackx.Client, dbRepo storage.StatsRepository, config *utils.Config) (*Bot, error) {
+	ctx, stop := context.WithCancel(ctx)
 	bot := &Bot{
-		slackClient: client,
-		config:      config,
-		ctx:         ctx,
-		repo:        dbRepo,
+		slackClient:    client,
+		config:         config,
+		ctx:            ctx,
+		stop:           stop,
+		repo:           dbRepo,
+		statsProcessor: NewStatsProcessor(config),
+	}
+	identity, err := client.AuthTestContext(ctx)
+	if err != nil {
+		return nil, err
 	}
+	bot.botUserID = identity.UserID
+	bot.statsProcessor.IgnoreUser(identity.UserID)
+
 	// Register event handlers
 	client.RegisterEventHandler("app_mention", bot.handleAppMentionEvent)
 	client.RegisterEventHandler("reaction_added", bot.handleReactionEvent)
+	client.RegisterEventHandler("reaction_removed", bot.handleReactionRemovedEvent)
+	client.RegisterEventHandler("message", bot.handleMessageEvent)
 
 	client.RegisterInteractiveHandler(slack.InteractionTypeShortcut, "pull_stats_for_interval", bot.handleInteractiveEvent)
 	client.RegisterInteractiveHandler(slack.InteractionTypeShortcut, "draw_stats_for_interval", bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeShortcut, "unmapped_reactions", bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeShortcut, "response_times", bot.handleInteractiveEvent)
 	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, "pull_stats_for_interval_modal", bot.handleInteractiveEvent)
 	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, "draw_stats_for_interval_modal", bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, "unmapped_reactions_modal", bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, "response_times_modal", bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeBlockActions, drillDownActionID, bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeBlockActions, categorizeActionID, bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeMessageAction, categorizeShortcutID, bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, categorizeModalID, bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeMessageAction, createTicketShortcutID, bot.handleInteractiveEvent)
+	client.RegisterInteractiveHandler(slack.InteractionTypeViewSubmission, createTicketModalID, bot.handleInteractiveEvent)
+
+	// rules may name workspace aliases, so they are loaded before the rules are indexed
+	bot.config.EmojiAliases = utils.NewEmojiAliases()
+	bot.refreshEmojiAliases()
+	if err := bot.config.BuildReactionCache(); err != nil {
+		return nil, fmt.Errorf("failed to build rule cache: %w", err)
+	}
+
+	businessHours, err := bot.loadBusinessHours()
+	if err != nil {
+		return nil, err
+	}
+	bot.businessHours = businessHours
 
-	bot.config.BuildReactionCache()
+	onCall, err := bot.loadOnCallSchedules()
+	if err != nil {
+		return nil, err
+	}
+	bot.onCall = onCall
+
+	issueTracker, err := newIssueTracker(config.IssueTracker)
+	if err != nil {
+		return nil, err
+	}
+	bot.issueTracker = issueTracker
+
+	webhooks, err := bot.newWebhookDispatcher(config.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+	bot.webhooks = webhooks
 	return bot, nil
 }
 
-// Run starts the bot's main loop.
+// Run starts the bot's main loop. Once it ends, the background loops are
+// stopped and the queued webhook deliveries are drained.
 func (b *Bot) Run() error {
 	log.Println("Starting TARS bot...")
-	return b.slackClient.ListenEvents(b.ctx)
+	var loops sync.WaitGroup
+	for _, loop := range []func(){b.runSyncer, b.runEmojiRefresher, b.runSLAMonitor, b.runReminders} {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			loop()
+		}()
+	}
+	err := b.slackClient.ListenEvents(b.ctx)
+	// the event loop may also end with an error, the loops only stop with the context
+	b.stop()
+	loops.Wait()
+	if b.webhooks != nil {
+		// deliveries give up once the context is done, failures are still recorded
+		b.webhooks.Close()
+	}
+	return err
 }
 
 func (b *Bot) handleInteractiveEvent(eventType slack.InteractionType, callback slack.InteractionCallback) error {
@@ -57,6 +144,10 @@ func (b *Bot) handleInteractiveEvent(eventType slack.InteractionType, callback s
 		return b.handleInteractiveShortcut(callback)
 	} else if eventType == slack.InteractionTypeViewSubmission {
 		return b.handleViewSubmission(callback)
+	} else if eventType == slack.InteractionTypeBlockActions {
+		return b.handleBlockActions(callback)
+	} else if eventType == slack.InteractionTypeMessageAction {
+		return b.handleMessageAction(callback)
 	}
 	log.Println("Unsupported interactive event type")
 	return nil
@@ -66,6 +157,14 @@ func (b *Bot) handleViewSubmission(callback slack.InteractionCallback) error {
 	switch callback.View.CallbackID {
 	case "pull_stats_for_interval_modal", "draw_stats_for_interval_modal":
 		return b.handlePullStatsForInterval(callback)
+	case "unmapped_reactions_modal":
+		return b.handleUnmappedReactionsReport(callback)
+	case "response_times_modal":
+		return b.handleResponseTimesReport(callback)
+	case categorizeModalID:
+		return b.handleCategorizeSubmission(callback)
+	case createTicketModalID:
+		return b.handleCreateTicketSubmission(callback)
 	default:
 		log.Printf("Unhandled view submission callback: %s", callback.View.CallbackID)
 		return nil
@@ -73,13 +172,24 @@ func (b *Bot) handleViewSubmission(callback slack.InteractionCallback) error {
 }
 func (b *Bot) handleInteractiveShortcut(callback slack.InteractionCallback) error {
 	switch callback.CallbackID {
-	case "pull_stats_for_interval", "draw_stats_for_interval":
+	case "pull_stats_for_interval", "draw_stats_for_interval", "unmapped_reactions", "response_times":
 		return b.openDatePickerModal(callback.CallbackID, callback.TriggerID)
 	default:
 		return nil
 	}
 }
 
+func (b *Bot) handleMessageAction(callback slack.InteractionCallback) error {
+	switch callback.CallbackID {
+	case categorizeShortcutID:
+		return b.openCategorizeModal(callback)
+	case createTicketShortcutID:
+		return b.openCreateTicketModal(callback)
+	default:
+		return nil
+	}
+}
+
 func (b *Bot) openDatePickerModal(modalType, triggerID string) error {
 	curDate := time.Now().Format("2006-01-02")
 
@@ -89,6 +199,13 @@ func (b *Bot) openDatePickerModal(modalType, triggerID string) error {
 	startDate.InitialDate = curDate
 	endDate.InitialDate = curDate
 
+	requestsOption := slack.NewOptionBlockObject(statsMetricRequests,
+		slack.NewTextBlockObject(slack.PlainTextType, "Requests (distinct messages)", false, false), nil)
+	reactionsOption := slack.NewOptionBlockObject(statsMetricReactions,
+		slack.NewTextBlockObject(slack.PlainTextType, "Reactions (every click)", false, false), nil)
+	metric := slack.NewRadioButtonsBlockElement("metric_picker", requestsOption, reactionsOption)
+	metric.InitialOption = requestsOption
+
 	modal := slack.ModalViewRequest{
 		Type:       slack.VTModal,
 		CallbackID: fmt.Sprintf("%s_modal", modalType),
@@ -128,6 +245,16 @@ func (b *Bot) openDatePickerModal(modalType, triggerID string) error {
 		},
 	}
 
+	// only charts have a metric to choose
+	if modalType == "pull_stats_for_interval" || modalType == "draw_stats_for_interval" {
+		modal.Blocks.BlockSet = append(modal.Blocks.BlockSet, slack.NewInputBlock(
+			"metric",
+			slack.NewTextBlockObject(slack.PlainTextType, "What to count 🔢", false, false),
+			nil,
+			metric,
+		))
+	}
+
 	_, err := b.slackClient.OpenViewContext(b.ctx, triggerID, modal)
 	if err != nil {
 		log.Printf("Error opening modal: %v", err)
@@ -152,14 +279,76 @@ func (b *Bot) handleReactionEvent(eventType string, rawEvent interface{}) error
 		return err
 	}
 	log.Printf("Reaction Added: %+v", event)
-	return nil
+	return b.applyReaction(reactionChange{
+		Item:     event.Item,
+		Reaction: event.Reaction,
+		User:     event.User,
+		ItemUser: event.ItemUser,
+		At:       eventTime(event.EventTimestamp),
+		Added:    true,
+	})
 }
 
-func (b *Bot) handlePullStatsForInterval(callback slack.InteractionCallback) error {
+// Handle reaction_removed event
+func (b *Bot) handleReactionRemovedEvent(eventType string, rawEvent interface{}) error {
+	event, err := utils.DecodeEvent[slackevents.ReactionRemovedEvent](rawEvent)
+	if err != nil {
+		return err
+	}
+	log.Printf("Reaction Removed: %+v", event)
+	return b.applyReaction(reactionChange{
+		Item:     event.Item,
+		Reaction: event.Reaction,
+		User:     event.User,
+		ItemUser: event.ItemUser,
+		At:       eventTime(event.EventTimestamp),
+		Added:    false,
+	})
+}
+
+// applyReaction applies a reaction change to the request of the reacted
+// message. Replies are never requests of their own: categorized reactions on
+// them count toward the request that started the thread if the channel counts
+// thread replies, other reactions on replies are dropped. Reactions of the bot
+// itself, like the category reaction of a manual categorization, are skipped.
+func (b *Bot) applyReaction(change reactionChange) error {
+	if change.Item.Type != "message" || !b.channelConfigExists(change.Item.Channel) || change.User == b.botUserID {
+		return nil
+	}
+
+	parentTS, err := b.threadParent(change.Item.Channel, change.Item.Timestamp)
+	if err != nil {
+		// a reply stored as a request would stay one for good
+		return fmt.Errorf("failed to find the thread of message %s: %w", change.Item.Timestamp, err)
+	}
+	if parentTS != change.Item.Timestamp {
+		if !b.countsThreadReplies(change.Item.Channel) || !b.countsOnReply(change.Item.Channel, change.Reaction) {
+			return nil
+		}
+		change.ReplyTS = change.Item.Timestamp
+		change.Item.Timestamp = parentTS
+		change.ItemUser = ""
+	}
+
+	return b.trackReaction(change)
+}
+
+// eventTime returns the time of an event, falling back to now when the
+// event timestamp is missing or malformed.
+func eventTime(timestamp string) time.Time {
+	at, err := parseTimestamp(timestamp)
+	if err != nil {
+		return time.Now()
+	}
+	return at
+}
+
+// statsInterval extracts the channel and the date range selected in a stats modal.
+func (b *Bot) statsInterval(callback slack.InteractionCallback) (string, time.Time, time.Time, error) {
 	// Extract channel and date range from the callback
 	channelID := callback.View.State.Values["channel_picker"]["channel_picker"].SelectedChannel
 	if channelID == "" {
-		return fmt.Errorf("channel is required")
+		return "", time.Time{}, time.Time{}, fmt.Errorf("channel is required")
 	}
 
 	if !b.channelConfigExists(channelID) {
@@ -168,39 +357,66 @@ func (b *Bot) handlePullStatsForInterval(callback slack.InteractionCallback) err
 		if errPost != nil {
 			log.Printf("Failed to send DM: %v", errPost)
 		}
-		return fmt.Errorf("channel %s is not configured", channelID)
+		return "", time.Time{}, time.Time{}, fmt.Errorf("channel %s is not configured", channelID)
 	}
 
 	// Extract the selected start date
 	startDateString := callback.View.State.Values["start_date"]["start_date_picker"].SelectedDate
 	startDate, err := time.Parse("2006-01-02", startDateString)
 	if err != nil {
-		return fmt.Errorf("invalid start date: %s", startDateString)
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %s", startDateString)
 	}
 
 	// Extract the selected end date
 	endDateString := callback.View.State.Values["end_date"]["end_date_picker"].SelectedDate
 	endDate, err := time.Parse("2006-01-02", endDateString)
 	if err != nil {
-		return fmt.Errorf("invalid end date: %s", endDateString)
+		return "", time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %s", endDateString)
 	}
 	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, time.UTC)
+	return channelID, startDate, endDate, nil
+}
+
+func (b *Bot) handlePullStatsForInterval(callback slack.InteractionCallback) error {
+	channelID, startDate, endDate, err := b.statsInterval(callback)
+	if err != nil {
+		return err
+	}
+
+	metric := callback.View.State.Values["metric"]["metric_picker"].SelectedOption.Value
+	if metric == "" {
+		metric = statsMetricRequests
+	}
 
 	if callback.View.CallbackID == "pull_stats_for_interval_modal" {
-		err = b.processChannelStats(channelID, startDate, endDate)
-		if err != nil {
-			errPost := b.postEphemeralError(channelID, callback.User.ID, err.Error())
-			if errPost != nil {
-				log.Printf("Failed to send error message: %v", errPost)
-			}
-			return err // return the original error
-		}
+		// Pulling history can take a while, so the chart is sent once it's done
+		go b.pullStatsAndReport(channelID, startDate, endDate, metric, callback.User.ID)
+		return nil
 	}
 
-	err = b.GenerateAndSendStatsPieChart(b.ctx, channelID, startDate, endDate, callback.User.ID)
+	err = b.GenerateAndSendStatsPieChart(b.ctx, channelID, startDate, endDate, metric, "", callback.User.ID)
 	return err
 }
 
+// pullStatsAndReport backfills the interval from the channel history and
+// sends the resulting chart to the user.
+func (b *Bot) pullStatsAndReport(channelID string, startDate, endDate time.Time, metric, userID string) {
+	notice := fmt.Sprintf("⏳ Pulling stats for <#%s>, I'll send the chart once it's ready.", channelID)
+	if err := b.postDM(userID, notice); err != nil {
+		log.Printf("Failed to send DM: %v", err)
+	}
+	if err := b.processChannelStats(channelID, startDate, endDate); err != nil {
+		log.Printf("Failed to pull stats for channel %s: %v", channelID, err)
+		if errPost := b.postEphemeralError(channelID, userID, err.Error()); errPost != nil {
+			log.Printf("Failed to send error message: %v", errPost)
+		}
+		return
+	}
+	if err := b.GenerateAndSendStatsPieChart(b.ctx, channelID, startDate, endDate, metric, "", userID); err != nil {
+		log.Printf("Failed to send stats chart: %v", err)
+	}
+}
+
 func (b *Bot) channelConfigExists(channelID string) bool {
 	for _, channel := range b.config.Channels {
 		if channel.ID == channelID {
@@ -227,82 +443,91 @@ func (b *Bot) postEphemeralError(channelID, userID, message string) error {
 	return nil
 }
 
+// processChannelStats pulls the channel history of the interval into storage.
 func (b *Bot) processChannelStats(channelID string, startDate, endDate time.Time) error {
 	// Fetch messages for the entire range in one go
 	messages, err := b.slackClient.FetchMessages(b.ctx, channelID, startDate, endDate.Add(24*time.Hour))
 	if err != nil {
 		return fmt.Errorf("failed to fetch messages: %w", err)
 	}
-	statsProcessor := NewStatsProcessor(b.config)
-	beaconReaction := utils.GetControllingReaction(b.config, channelID)
 	log.Printf("Fetched %d messages for range %s to %s", len(messages), startDate, endDate)
 
-	// Organize stats by day
-	statsByDay := make(map[string]map[string]int) // date -> category -> count
-	for _, message := range messages {
-		timestampFloat, err := strconv.ParseFloat(message.Timestamp, 64)
-		if err != nil {
-			log.Printf("Invalid timestamp for message: %s, error: %v", message.Timestamp, err)
-			continue
-		}
-		msgDate := time.Unix(int64(timestampFloat), 0).Format("2006-01-02")
-		log.Printf("Message Date: %s", msgDate)
-		if !statsProcessor.ShouldProcessMessage(channelID, message, beaconReaction) {
-			continue
-		}
-
-		reactions, err := b.slackClient.FetchReactions(b.ctx, channelID, message.Timestamp)
-		if err != nil {
-			log.Printf("Failed to fetch reactions for message %s: %v", message.Timestamp, err)
-			continue
-		}
-
-		if _, exists := statsByDay[msgDate]; !exists {
-			statsByDay[msgDate] = make(map[string]int)
-		}
-		statsProcessor.UpdateStats(channelID, reactions, statsByDay[msgDate])
-	}
-
-	// Save stats for each day
-	for day, stats := range statsByDay {
-		date, _ := time.Parse("2006-01-02", day)
-		if err := b.saveStatsToDB(channelID, date, stats); err != nil {
-			log.Printf("Failed to save stats for %s: %v", day, err)
-		}
+	if _, failed := b.processMessages(channelID, messages); len(failed) > 0 {
+		return fmt.Errorf("failed to process %d of %d messages, the stats may be incomplete", len(failed), len(messages))
 	}
 	return nil
 }
 
-func (b *Bot) saveStatsToDB(channelID string, date time.Time, stats map[string]int) error {
-	return b.repo.SaveStats(channelID, date, stats)
+// messageDate returns the day a message was posted on, based on its Slack timestamp.
+func messageDate(timestamp string) (time.Time, error) {
+	postedAt, err := parseTimestamp(timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := postedAt.Format("2006-01-02")
+	return time.Parse("2006-01-02", day)
 }
 
-func (b *Bot) GenerateAndSendStatsPieChart(ctx context.Context, channelID string, startDate, endDate time.Time, userID string) error {
+// GenerateAndSendStatsPieChart sends the user a pie chart of the channel
+// stats in the interval, counting the given metric. Categories are rolled up
+// to the top level, or to the children of parent when drilling down.
+func (b *Bot) GenerateAndSendStatsPieChart(ctx context.Context, channelID string, startDate, endDate time.Time, metric, parent, userID string) error {
+	tree := storage.CategoryTree(utils.GetCategoryTree(b.config, channelID))
+	level := 0
+	if parent != "" {
+		level = tree.Depth(parent) + 1
+	}
+
 	// Fetch stats from DB
-	stats, err := b.repo.GetAggregatedStats(channelID, startDate, endDate)
+	stats, err := b.repo.GetRolledUpStats(storage.RollUpQuery{
+		Channel: channelID,
+		Start:   startDate,
+		End:     endDate,
+		Tree:    tree,
+		Level:   level,
+		Parent:  parent,
+		Filter:  b.countedIn(channelID),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to fetch stats: %w", err)
 	}
 
-	// Check if stats are empty
-	if len(stats) == 0 {
-		_, _, err := b.slackClient.PostMessageContext(ctx, userID, slack.MsgOptionText("📉 No stats available for this period.", false))
-		return err
+	title := "Requests per Category"
+	if metric == statsMetricReactions {
+		title = "Reactions per Category"
+	}
+	if parent != "" {
+		title = fmt.Sprintf("%s in %s", title, parent)
 	}
 
-	// Aggregate stats by category
+	// Aggregate stats by category, skipping the emptied ones
 	names := []string{}
 	values := []float64{}
 	for _, stat := range stats {
-		names = append(names, stat.Category)
-		values = append(values, float64(stat.Count))
+		count := stat.Requests
+		if metric == statsMetricReactions {
+			count = stat.Count
+		}
+		if count > 0 {
+			names = append(names, stat.Category)
+			values = append(values, float64(count))
+		}
+	}
+
+	// Check if stats are empty
+	if len(names) == 0 {
+		_, _, err := b.slackClient.PostMessageContext(ctx, userID, slack.MsgOptionText("📉 No stats available for this period.", false))
+		return err
 	}
 
+	subtext := fmt.Sprintf("From %s to %s, category policy: %s",
+		startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), utils.GetCategoryPolicy(b.config, channelID))
+
 	p, err := charts.PieRender(
 		values,
 		charts.TitleOptionFunc(charts.TitleOption{
-			Text:    "Reaction Stats Pie Chart",
-			Subtext: fmt.Sprintf("From %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")),
+			Text:    title,
+			Subtext: subtext,
 			Left:    charts.PositionCenter,
 		}),
 		charts.PaddingOptionFunc(charts.Box{
@@ -324,25 +549,49 @@ func (b *Bot) GenerateAndSendStatsPieChart(ctx context.Context, channelID string
 		return fmt.Errorf("failed to render chart: %w", err)
 	}
 
-	// Save the chart as an image
-	filePath := "/tmp/stats_pie_chart.png"
+	// Save the chart as an image, to a file of its own since reports can be
+	// drawn at the same time
 	buf, err := p.Bytes()
 	if err != nil {
 		return fmt.Errorf("failed to generate chart bytes: %w", err)
 	}
-	err = os.WriteFile(filePath, buf, 0644)
+	file, err := os.CreateTemp("", "stats_pie_chart_*.png")
+	if err != nil {
+		return fmt.Errorf("failed to create chart file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(buf)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		return fmt.Errorf("failed to save chart to file: %w", err)
 	}
 
 	// Upload to Slack
-	err = b.uploadGraphToSlack(userID, filePath, "📊 Reaction Stats Pie Chart")
+	err = b.uploadGraphToSlack(userID, file.Name(), "📊 "+title)
 	if err != nil {
 		return fmt.Errorf("failed to upload chart: %w", err)
 	}
-	os.Remove(filePath)
 
-	return nil
+	if parent == "" {
+		if err := b.postWorkload(ctx, userID, channelID, startDate, endDate); err != nil {
+			log.Printf("Failed to send workload of %s: %v", channelID, err)
+		}
+	}
+
+	var drillable []string
+	for _, name := range names {
+		if name != parent && tree.HasChildren(name) {
+			drillable = append(drillable, name)
+		}
+	}
+	return b.postDrillDownButtons(ctx, userID, drillDown{
+		Channel: channelID,
+		Start:   startDate.Format("2006-01-02"),
+		End:     endDate.Format("2006-01-02"),
+		Metric:  metric,
+	}, drillable)
 }
 
 func (b *Bot) uploadGraphToSlack(userID string, filePath string, title string) error {
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	slackx "github.com/artemlive/tars/pkg/slack"
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testBotUser = "U0BOT"
+
+// newTestConfig returns a channel with a beacon, lifecycle reactions and a
+// reaction and a text rule.
+func newTestConfig() *utils.Config {
+	return &utils.Config{
+		Channels: []utils.ChannelConfig{{
+			ID:               "C123",
+			BeaconReaction:   ":eyes:",
+			AckReaction:      ":raising_hand:",
+			ResolvedReaction: ":white_check_mark:",
+			Rules: []utils.RuleConfig{
+				{Reaction: "bug", Category: "Infra bug"},
+				{Keywords: []string{"jenkins"}, Category: "CI/CD"},
+			},
+		}},
+	}
+}
+
+// newTestBot creates a bot on an in-memory database and a mock Slack client.
+func newTestBot(t *testing.T, config *utils.Config) (*Bot, *slackx.MockClient) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	// every connection would get a database of its own
+	sqlDB.SetMaxOpenConns(1)
+	err = storage.Migrate(db, &storage.Stats{}, &storage.Request{}, &storage.RequestCategory{}, &storage.SyncCheckpoint{},
+		&storage.SyncFailure{}, &storage.SLABreach{}, &storage.WebhookFailure{})
+	assert.NoError(t, err)
+
+	config.EmojiAliases = utils.NewEmojiAliases()
+	assert.NoError(t, config.BuildReactionCache())
+
+	client := slackx.NewMockClient(gomock.NewController(t))
+	bot := &Bot{
+		slackClient:    client,
+		config:         config,
+		ctx:            context.Background(),
+		repo:           storage.NewSQLiteStatsRepository(db),
+		statsProcessor: NewStatsProcessor(config),
+		botUserID:      testBotUser,
+	}
+	bot.statsProcessor.IgnoreUser(testBotUser)
+	return bot, client
+}
+
+// testMessage builds a history message, threadTS is empty for messages
+// without a thread.
+func testMessage(ts, threadTS, user string, reactions ...slack.ItemReaction) slack.Message {
+	return slack.Message{Msg: slack.Msg{
+		Timestamp:       ts,
+		ThreadTimestamp: threadTS,
+		User:            user,
+		Reactions:       reactions,
+	}}
+}
+
+// testReaction builds a reaction added by the users.
+func testReaction(name string, users ...string) slack.ItemReaction {
+	return slack.ItemReaction{Name: name, Count: len(users), Users: users}
+}
+
+// testReactionChange builds a live reaction change on a message of C123.
+func testReactionChange(messageTS, reaction, user string, added bool) reactionChange {
+	at, _ := parseTimestamp(messageTS)
+	return reactionChange{
+		Item:     slackevents.Item{Type: "message", Channel: "C123", Timestamp: messageTS},
+		Reaction: reaction,
+		User:     user,
+		At:       at.Add(time.Minute),
+		Added:    added,
+	}
+}
+
+func TestGenerateAndSendStatsPieChart_File(t *testing.T) {
+	const messageTS = "1738144800.000100"
+	bot, client := newTestBot(t, newTestConfig())
+
+	reactions := []slack.ItemReaction{testReaction("bug", "U1"), testReaction("eyes", "U2")}
+	_, _, err := bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+	day, err := messageDate(messageTS)
+	assert.NoError(t, err)
+	assert.NoError(t, bot.rebuildDailyStats("C123", day))
+
+	var files []string
+	client.EXPECT().OpenConversationContext(gomock.Any(), gomock.Any()).Return(&slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D1"}}}, false, false, nil).Times(2)
+	client.EXPECT().UploadFileV2Context(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+		_, err := os.Stat(params.File)
+		assert.NoError(t, err, "The chart should exist while it is uploaded")
+		files = append(files, params.File)
+		return &slack.FileSummary{}, nil
+	}).Times(2)
+	client.EXPECT().PostMessageContext(gomock.Any(), "U1", gomock.Any()).Return("", "", nil).AnyTimes()
+
+	start, end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, bot.GenerateAndSendStatsPieChart(context.Background(), "C123", start, end, statsMetricRequests, "", "U1"))
+	}
+
+	assert.Len(t, files, 2)
+	assert.NotEqual(t, files[0], files[1], "Every render should get a file of its own")
+	for _, file := range files {
+		_, err := os.Stat(file)
+		assert.ErrorIs(t, err, os.ErrNotExist, "The chart should be removed after the upload")
+	}
+}
+
+func TestHandleReactionRemovedEvent(t *testing.T) {
+	const messageTS = "1738144800.000100"
+	bot, _ := newTestBot(t, newTestConfig())
+
+	reactions := []slack.ItemReaction{
+		testReaction("bug", "U1", "U2"), testReaction("eyes", "U3"), testReaction("white_check_mark", "U4"),
+	}
+	request, _, err := bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+	assert.NotNil(t, request.ResolvedAt)
+	day, err := messageDate(messageTS)
+	assert.NoError(t, err)
+	assert.NoError(t, bot.rebuildDailyStats("C123", day))
+
+	removed := func(reaction, user string) slackevents.ReactionRemovedEvent {
+		return slackevents.ReactionRemovedEvent{
+			Type:           "reaction_removed",
+			User:           user,
+			Reaction:       reaction,
+			Item:           slackevents.Item{Type: "message", Channel: "C123", Timestamp: messageTS},
+			EventTimestamp: "1738144900.000100",
+		}
+	}
+	start, end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name      string
+		event     slackevents.ReactionRemovedEvent
+		reactions map[string]int
+		resolved  bool
+		bugs      int // reactions counted in Infra bug, 0 if the request left it
+	}{
+		{
+			name:      "one of two reactions",
+			event:     removed("bug", "U1"),
+			reactions: map[string]int{"bug": 1, "eyes": 1, "white_check_mark": 1},
+			resolved:  true,
+			bugs:      1,
+		},
+		{
+			name:      "resolution reaction",
+			event:     removed("white_check_mark", "U4"),
+			reactions: map[string]int{"bug": 1, "eyes": 1},
+			bugs:      1,
+		},
+		{
+			name:      "last reaction of the category",
+			event:     removed("bug", "U2"),
+			reactions: map[string]int{"eyes": 1},
+		},
+		{
+			name:      "reaction that isn't there",
+			event:     removed("bug", "U2"),
+			reactions: map[string]int{"eyes": 1},
+		},
+	}
+
+	for _, step := range steps {
+		assert.NoError(t, bot.handleReactionRemovedEvent("reaction_removed", step.event), step.name)
+		request, err := bot.repo.GetRequest("C123", messageTS)
+		assert.NoError(t, err, step.name)
+		assert.Equal(t, step.reactions, request.Reactions, step.name)
+		assert.Equal(t, step.resolved, request.ResolvedAt != nil, step.name)
+
+		stats, err := bot.repo.GetAggregatedStats("C123", start, end)
+		assert.NoError(t, err, step.name)
+		bugs := 0
+		for _, stat := range stats {
+			if stat.Category == "Infra bug" && stat.Requests > 0 {
+				bugs = stat.Count
+			}
+		}
+		assert.Equal(t, step.bugs, bugs, step.name)
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/artemlive/tars/pkg/calendar"
+	"github.com/artemlive/tars/pkg/storage"
+)
+
+// loadBusinessHours loads the business hours calendar, nil if none is configured.
+func (b *Bot) loadBusinessHours() (*calendar.Calendar, error) {
+	hours := b.config.BusinessHours
+	if len(hours.Hours) == 0 {
+		return nil, nil
+	}
+	cal, err := calendar.Load(hours.Timezone, hours.Hours, hours.HolidaysFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid business hours: %w", err)
+	}
+	return cal, nil
+}
+
+// elapsed returns how durations are measured: in business time if business
+// hours are configured, wall clock time otherwise.
+func (b *Bot) elapsed() storage.ElapsedFunc {
+	if b.businessHours == nil {
+		return nil
+	}
+	return b.businessHours.Elapsed
+}
+
+// dueAt returns when a target of the given duration since from is due,
+// counting business time only if business hours are configured.
+func (b *Bot) dueAt(from time.Time, within time.Duration) time.Time {
+	if b.businessHours == nil {
+		return from.Add(within)
+	}
+	return b.businessHours.Add(from, within)
+}
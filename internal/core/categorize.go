@@ -0,0 +1,280 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// categorizeActionID identifies the buttons that categorize a request.
+const categorizeActionID = "categorize_request"
+
+// maxCategorizeButtons keeps the message within the Slack limit of 50 blocks.
+const maxCategorizeButtons = 25
+
+// maxCategoryOptions is the Slack limit of options in a static select.
+const maxCategoryOptions = 100
+
+// Callback IDs of the message shortcut that categorizes a request and its modal.
+const (
+	categorizeShortcutID = "categorize_message"
+	categorizeModalID    = "categorize_message_modal"
+)
+
+// categorizeAction is the category a categorize button picks, stored in the
+// button value. The categorize modal keeps the message in its metadata.
+type categorizeAction struct {
+	Channel   string `json:"channel"`
+	MessageTS string `json:"message_ts"`
+	Category  string `json:"category"`
+}
+
+// handleMessageEvent answers new top-level messages of channels with
+// categorize buttons and records thread replies as activity on their requests.
+func (b *Bot) handleMessageEvent(eventType string, rawEvent interface{}) error {
+	event, err := utils.DecodeEvent[slackevents.MessageEvent](rawEvent)
+	if err != nil {
+		return err
+	}
+	channel, exists := utils.GetChannelConfig(b.config, event.Channel)
+	if !exists {
+		return nil
+	}
+	// edits, joins and bot messages have a subtype
+	if event.SubType != "" || event.BotID != "" {
+		return nil
+	}
+	// replies have a different thread
+	if event.ThreadTimeStamp != "" && event.ThreadTimeStamp != event.TimeStamp {
+		return b.trackReply(event.Channel, event.ThreadTimeStamp, event.TimeStamp)
+	}
+	if !channel.CategorizeButtons {
+		return nil
+	}
+
+	blocks, err := categorizeBlocks(b.config, event.Channel, event.TimeStamp, "🏷️ Pick a category for this request:")
+	if err != nil || len(blocks) == 0 {
+		return err
+	}
+	_, _, err = b.slackClient.PostMessageContext(b.ctx, event.Channel,
+		slack.MsgOptionBlocks(blocks...), slack.MsgOptionText("Pick a category for this request", false), slack.MsgOptionTS(event.TimeStamp))
+	if err != nil {
+		return fmt.Errorf("failed to post categorize buttons: %w", err)
+	}
+	return nil
+}
+
+// categorizeBlocks builds a message with the status and a button per category
+// of the channel, nil if the channel has no categories.
+func categorizeBlocks(config *utils.Config, channelID, messageTS, status string) ([]slack.Block, error) {
+	categories := utils.GetChannelCategories(config, channelID)
+	if len(categories) == 0 {
+		return nil, nil
+	}
+	if len(categories) > maxCategorizeButtons {
+		log.Printf("Channel %s has %d categories, only the first %d get a button", channelID, len(categories), maxCategorizeButtons)
+		categories = categories[:maxCategorizeButtons]
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, status, false, false), nil, nil),
+	}
+	for _, category := range categories {
+		value, err := json.Marshal(categorizeAction{Channel: channelID, MessageTS: messageTS, Category: category})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode categorize action: %w", err)
+		}
+		label := category
+		if reaction, ok := utils.GetReactionForCategory(config, channelID, category); ok {
+			label = fmt.Sprintf(":%s: %s", reaction, category)
+		}
+		// action IDs have to be unique within a block, so every button gets its own
+		button := slack.NewButtonBlockElement(categorizeActionID, string(value),
+			slack.NewTextBlockObject(slack.PlainTextType, label, true, false))
+		blocks = append(blocks, slack.NewActionBlock("", button))
+	}
+	return blocks, nil
+}
+
+// handleCategorizeButton records the picked category, adds its reaction to the
+// message and updates the button message to show who picked it.
+func (b *Bot) handleCategorizeButton(value string, callback slack.InteractionCallback) error {
+	var action categorizeAction
+	if err := json.Unmarshal([]byte(value), &action); err != nil {
+		return fmt.Errorf("invalid categorize action %q: %w", value, err)
+	}
+	if err := b.categorizeRequest(action.Channel, action.MessageTS, action.Category, callback.User.ID, ""); err != nil {
+		return err
+	}
+	b.addCategoryReaction(action.Channel, action.MessageTS, action.Category)
+
+	status := fmt.Sprintf("🏷️ Categorized as *%s* by <@%s>.", action.Category, callback.User.ID)
+	blocks, err := categorizeBlocks(b.config, action.Channel, action.MessageTS, status)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = b.slackClient.UpdateMessageContext(b.ctx, callback.Channel.ID, callback.Message.Timestamp,
+		slack.MsgOptionBlocks(blocks...), slack.MsgOptionText(status, false))
+	if err != nil {
+		return fmt.Errorf("failed to update categorize buttons: %w", err)
+	}
+	return nil
+}
+
+// categorizeRequest records the category a person picked for the request of
+// the message, which overrides the rules, and updates the stats of its day.
+func (b *Bot) categorizeRequest(channelID, messageTS, category, userID, note string) error {
+	date, err := messageDate(messageTS)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := b.prefetchMessage(channelID, messageTS)
+	if err != nil {
+		return err
+	}
+
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+
+	before := make(map[string]storage.CategoryStats)
+	request, stored, err := b.loadRequest(channelID, messageTS, "", snapshot)
+	if err != nil {
+		return err
+	}
+	if stored {
+		before = b.statsProcessor.RequestStats(channelID, request)
+	}
+	state := b.requestState(channelID, request, stored)
+
+	request.ManualCategory = category
+	request.CategorizedBy = userID
+	request.CategoryNote = note
+	if request.CategorizedAt == nil {
+		now := time.Now()
+		request.CategorizedAt = &now
+	}
+	b.statsProcessor.Categorize(channelID, request)
+	if err := b.repo.SaveRequest(request); err != nil {
+		return err
+	}
+	log.Printf("Request %s in %s categorized as %s by %s", messageTS, channelID, category, userID)
+	b.emitRequestChanges(channelID, request, state)
+	return b.applyStatsDiff(channelID, date, before, b.statsProcessor.RequestStats(channelID, request))
+}
+
+// addCategoryReaction adds the reaction of the category to the message, so
+// the category shows on the message itself.
+func (b *Bot) addCategoryReaction(channelID, messageTS, category string) {
+	reaction, ok := utils.GetReactionForCategory(b.config, channelID, category)
+	if !ok {
+		return
+	}
+	err := b.slackClient.AddReactionContext(b.ctx, reaction, slack.ItemRef{Channel: channelID, Timestamp: messageTS})
+	if err != nil && !strings.Contains(err.Error(), "already_reacted") {
+		log.Printf("Failed to add reaction %s to message %s: %v", reaction, messageTS, err)
+	}
+}
+
+// openCategorizeModal opens the modal to pick the category of the message
+// the shortcut was used on. Replies are categorized with their thread.
+func (b *Bot) openCategorizeModal(callback slack.InteractionCallback) error {
+	channelID := callback.Channel.ID
+	if !b.channelConfigExists(channelID) {
+		return b.postEphemeralError(channelID, callback.User.ID, "this channel is not configured for stats")
+	}
+	categories := utils.GetChannelCategories(b.config, channelID)
+	if len(categories) == 0 {
+		return b.postEphemeralError(channelID, callback.User.ID, "this channel has no categories")
+	}
+	if len(categories) > maxCategoryOptions {
+		categories = categories[:maxCategoryOptions]
+	}
+
+	messageTS := callback.Message.Timestamp
+	if callback.Message.ThreadTimestamp != "" {
+		messageTS = callback.Message.ThreadTimestamp
+	}
+	metadata, err := json.Marshal(categorizeAction{Channel: channelID, MessageTS: messageTS})
+	if err != nil {
+		return fmt.Errorf("failed to encode categorize modal metadata: %w", err)
+	}
+
+	options := make([]*slack.OptionBlockObject, 0, len(categories))
+	for _, category := range categories {
+		options = append(options, slack.NewOptionBlockObject(category,
+			slack.NewTextBlockObject(slack.PlainTextType, category, false, false), nil))
+	}
+	note := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject(slack.PlainTextType, "Why this category?", false, false), "note_input")
+	note.Multiline = true
+	noteBlock := slack.NewInputBlock("note", slack.NewTextBlockObject(slack.PlainTextType, "Note 📝", false, false), nil, note)
+	noteBlock.Optional = true
+
+	modal := slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      categorizeModalID,
+		PrivateMetadata: string(metadata),
+		Title: &slack.TextBlockObject{
+			Type: slack.PlainTextType,
+			Text: "Categorize request🏷️",
+		},
+		Blocks: slack.Blocks{
+			BlockSet: []slack.Block{
+				slack.NewInputBlock(
+					"category",
+					slack.NewTextBlockObject(slack.PlainTextType, "Select the category: 🗂️", false, false),
+					nil,
+					slack.NewOptionsSelectBlockElement(
+						slack.OptTypeStatic,
+						slack.NewTextBlockObject(slack.PlainTextType, "Select a category", false, false),
+						"category_picker",
+						options...,
+					),
+				),
+				noteBlock,
+			},
+		},
+		Submit: &slack.TextBlockObject{
+			Type: slack.PlainTextType,
+			Text: "Submit",
+		},
+	}
+
+	_, err = b.slackClient.OpenViewContext(b.ctx, callback.TriggerID, modal)
+	if err != nil {
+		log.Printf("Error opening modal: %v", err)
+	}
+	return err
+}
+
+// handleCategorizeSubmission records the category picked in the categorize
+// modal, whether or not anyone reacted to the message.
+func (b *Bot) handleCategorizeSubmission(callback slack.InteractionCallback) error {
+	var target categorizeAction
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &target); err != nil {
+		return fmt.Errorf("invalid categorize modal metadata: %w", err)
+	}
+	category := callback.View.State.Values["category"]["category_picker"].SelectedOption.Value
+	if category == "" {
+		return fmt.Errorf("category is required")
+	}
+	note := strings.TrimSpace(callback.View.State.Values["note"]["note_input"].Value)
+
+	if err := b.categorizeRequest(target.Channel, target.MessageTS, category, callback.User.ID, note); err != nil {
+		return err
+	}
+	text := fmt.Sprintf("🏷️ The request is categorized as *%s*.", category)
+	_, err := b.slackClient.PostEphemeralContext(b.ctx, target.Channel, callback.User.ID, slack.MsgOptionText(text, false))
+	if err != nil {
+		return fmt.Errorf("failed to confirm the category: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCategorizeButton(t *testing.T) {
+	const (
+		messageTS = "1738144800.000100"
+		buttonsTS = "1738144801.000100"
+	)
+	bot, client := newTestBot(t, newTestConfig())
+
+	message := testMessage(messageTS, "", "U9", testReaction("eyes", "U1"))
+	message.Text = "the build is stuck"
+	client.EXPECT().FetchReplies(gomock.Any(), "C123", messageTS).Return([]slack.Message{message}, nil)
+	client.EXPECT().GetPermalinkContext(gomock.Any(), gomock.Any()).Return("https://example.slack.com/p1", nil)
+	client.EXPECT().AddReactionContext(gomock.Any(), "bug", slack.ItemRef{Channel: "C123", Timestamp: messageTS}).Return(nil)
+	client.EXPECT().UpdateMessageContext(gomock.Any(), "C123", buttonsTS, gomock.Any()).Return("", "", "", nil)
+
+	value, err := json.Marshal(categorizeAction{Channel: "C123", MessageTS: messageTS, Category: "Infra bug"})
+	assert.NoError(t, err)
+	callback := slack.InteractionCallback{
+		Type:    slack.InteractionTypeBlockActions,
+		User:    slack.User{ID: "U5"},
+		Channel: slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C123"}}},
+		Message: slack.Message{Msg: slack.Msg{Timestamp: buttonsTS}},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: categorizeActionID, Value: string(value)},
+		}},
+	}
+	assert.NoError(t, bot.handleInteractiveEvent(slack.InteractionTypeBlockActions, callback))
+
+	request, err := bot.repo.GetRequest("C123", messageTS)
+	assert.NoError(t, err)
+	assert.Equal(t, "Infra bug", request.ManualCategory)
+	assert.Equal(t, "U5", request.CategorizedBy)
+	assert.NotNil(t, request.CategorizedAt)
+	assert.Equal(t, []string{"Infra bug"}, request.CategoryNames())
+
+	// the reaction the bot added doesn't count on top of the pick
+	assert.NoError(t, bot.applyReaction(testReactionChange(messageTS, "bug", testBotUser, true)))
+	request, err = bot.repo.GetRequest("C123", messageTS)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"eyes": 1}, request.Reactions)
+
+	stats, err := bot.repo.GetAggregatedStats("C123", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "Infra bug", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Requests)
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// drillDownActionID identifies the buttons that open the chart of a parent category.
+const drillDownActionID = "drill_down_stats"
+
+// drillDown is the chart a drill-down button opens, stored in the button value.
+type drillDown struct {
+	Channel string `json:"channel"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Metric  string `json:"metric"`
+	Parent  string `json:"parent"`
+}
+
+// postDrillDownButtons sends the user a button for every category of the
+// chart that has child categories.
+func (b *Bot) postDrillDownButtons(ctx context.Context, userID string, chart drillDown, categories []string) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "🔍 Drill down into a category:", false, false), nil, nil),
+	}
+	for _, category := range categories {
+		chart.Parent = category
+		value, err := json.Marshal(chart)
+		if err != nil {
+			return fmt.Errorf("failed to encode drill-down: %w", err)
+		}
+		// action IDs have to be unique within a block, so every button gets its own
+		button := slack.NewButtonBlockElement(drillDownActionID, string(value),
+			slack.NewTextBlockObject(slack.PlainTextType, category, false, false))
+		blocks = append(blocks, slack.NewActionBlock("", button))
+	}
+
+	_, _, err := b.slackClient.PostMessageContext(ctx, userID, slack.MsgOptionBlocks(blocks...))
+	if err != nil {
+		return fmt.Errorf("failed to send drill-down buttons: %w", err)
+	}
+	return nil
+}
+
+func (b *Bot) handleBlockActions(callback slack.InteractionCallback) error {
+	for _, action := range callback.ActionCallback.BlockActions {
+		switch action.ActionID {
+		case drillDownActionID:
+			return b.handleDrillDown(action.Value, callback.User.ID)
+		case categorizeActionID:
+			return b.handleCategorizeButton(action.Value, callback)
+		default:
+			log.Printf("Unhandled block action: %s", action.ActionID)
+		}
+	}
+	return nil
+}
+
+// handleDrillDown sends the chart of the child categories of the clicked category.
+func (b *Bot) handleDrillDown(value, userID string) error {
+	var chart drillDown
+	if err := json.Unmarshal([]byte(value), &chart); err != nil {
+		return fmt.Errorf("invalid drill-down: %w", err)
+	}
+	startDate, err := time.Parse("2006-01-02", chart.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %s", chart.Start)
+	}
+	endDate, err := time.Parse("2006-01-02", chart.End)
+	if err != nil {
+		return fmt.Errorf("invalid end date: %s", chart.End)
+	}
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 0, time.UTC)
+
+	return b.GenerateAndSendStatsPieChart(b.ctx, chart.Channel, startDate, endDate, chart.Metric, chart.Parent, userID)
+}
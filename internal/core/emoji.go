@@ -0,0 +1,50 @@
+package core
+
+import (
+	"log"
+	"time"
+
+	"github.com/artemlive/tars/pkg/utils"
+)
+
+// emojiRefreshInterval is how often the workspace emoji aliases are reloaded.
+const emojiRefreshInterval = time.Hour
+
+// refreshEmojiAliases reloads the workspace emoji aliases from emoji.list
+// into the local cache. The previous aliases are kept if the call fails.
+// It reports whether the aliases were reloaded.
+func (b *Bot) refreshEmojiAliases() bool {
+	emoji, err := b.slackClient.GetEmojiContext(b.ctx)
+	if err != nil {
+		log.Printf("Failed to load emoji aliases: %v", err)
+		return false
+	}
+	if b.config.EmojiAliases == nil {
+		b.config.EmojiAliases = utils.NewEmojiAliases()
+	}
+	b.config.EmojiAliases.Update(emoji)
+	log.Printf("Loaded %d workspace emoji", len(emoji))
+	return true
+}
+
+// runEmojiRefresher keeps the emoji aliases up to date until the bot context
+// is canceled. Rules are indexed by resolved reaction names, so the rule
+// caches are rebuilt after every reload.
+func (b *Bot) runEmojiRefresher() {
+	ticker := time.NewTicker(emojiRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-ticker.C:
+			if !b.refreshEmojiAliases() {
+				continue
+			}
+			if err := b.config.BuildReactionCache(); err != nil {
+				// the config was valid at startup, so the previous caches stay in use
+				log.Printf("Failed to rebuild rule cache after reloading emoji aliases: %v", err)
+			}
+		}
+	}
+}
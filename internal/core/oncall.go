@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/artemlive/tars/pkg/oncall"
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/slack-go/slack"
+)
+
+// loadOnCallSchedules loads the on-call schedules of the channels that have one.
+func (b *Bot) loadOnCallSchedules() (map[string]oncall.Schedule, error) {
+	schedules := make(map[string]oncall.Schedule)
+	for _, channel := range b.config.Channels {
+		config := channel.OnCall
+		switch {
+		case config.ICSFile != "":
+			schedule, err := oncall.LoadICS(config.ICSFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid on-call schedule of channel %s: %w", channel.ID, err)
+			}
+			schedules[channel.ID] = schedule
+		case len(config.Users) > 0:
+			start, err := time.Parse(time.RFC3339, config.Start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid on-call start of channel %s: %w", channel.ID, err)
+			}
+			shift := config.ShiftLength
+			if shift == 0 {
+				shift = oncall.DefaultShiftLength
+			}
+			rotation, err := oncall.NewRotation(config.Users, start, shift)
+			if err != nil {
+				return nil, fmt.Errorf("invalid on-call rotation of channel %s: %w", channel.ID, err)
+			}
+			schedules[channel.ID] = rotation
+		}
+	}
+	return schedules, nil
+}
+
+// assignOnCall assigns the request to whoever is on call in the channel at
+// the time. It reports whether someone was assigned.
+func (b *Bot) assignOnCall(channelID string, request *storage.Request, at time.Time) bool {
+	schedule, exists := b.onCall[channelID]
+	if !exists || request.Assignee != "" {
+		return false
+	}
+	user, ok := schedule.OnCall(at)
+	if !ok {
+		log.Printf("Nobody is on call in %s at %s", channelID, at.Format(time.RFC3339))
+		return false
+	}
+	request.Assignee = user
+	return true
+}
+
+// announceAssignee mentions the assignee in the request thread.
+func (b *Bot) announceAssignee(channelID string, request *storage.Request) {
+	text := fmt.Sprintf("👋 <@%s> is on call and has been assigned this request.", request.Assignee)
+	_, _, err := b.slackClient.PostMessageContext(b.ctx, channelID, slack.MsgOptionText(text, false), slack.MsgOptionTS(request.MessageTS))
+	if err != nil {
+		log.Printf("Failed to announce the assignee of request %s: %v", request.MessageTS, err)
+	}
+}
+
+// postWorkload sends the user how many requests of the channel each person
+// was assigned in the interval, if anyone was.
+func (b *Bot) postWorkload(ctx context.Context, userID, channelID string, startDate, endDate time.Time) error {
+	workload, err := b.repo.GetWorkload(storage.RequestQuery{
+		Channel: channelID,
+		Start:   startDate,
+		End:     endDate,
+		Filter:  b.countedIn(channelID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch workload: %w", err)
+	}
+	if len(workload) == 0 {
+		return nil
+	}
+
+	var report strings.Builder
+	report.WriteString("👥 Workload per person:\n")
+	for _, w := range workload {
+		fmt.Fprintf(&report, "• <@%s>: %d requests, %d resolved\n", w.Assignee, w.Requests, w.Resolved)
+	}
+	_, _, err = b.slackClient.PostMessageContext(ctx, userID, slack.MsgOptionText(report.String(), false))
+	if err != nil {
+		return fmt.Errorf("failed to send workload: %w", err)
+	}
+	return nil
+}
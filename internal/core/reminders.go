@@ -0,0 +1,161 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+)
+
+const (
+	reminderCheckInterval = time.Minute         // how often open requests are checked for reminders
+	reminderLookback      = 30 * 24 * time.Hour // requests posted earlier aren't reminded about anymore
+)
+
+// runReminders periodically reminds about the stale requests of the channels
+// with reminders until the bot context is canceled.
+func (b *Bot) runReminders() {
+	ticker := time.NewTicker(reminderCheckInterval)
+	defer ticker.Stop()
+	for {
+		for _, channel := range b.config.Channels {
+			if channel.Reminders.IdleAfter <= 0 {
+				continue
+			}
+			if b.ctx.Err() != nil {
+				return
+			}
+			if err := b.remindStaleRequests(channel); err != nil {
+				log.Printf("Failed to remind about stale requests of channel %s: %v", channel.ID, err)
+			}
+		}
+
+		select {
+		case <-b.ctx.Done():
+			log.Println("Reminders stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// remindStaleRequests posts reminders for the beaconed requests of the channel
+// that aren't resolved and had no activity for the idle time.
+func (b *Bot) remindStaleRequests(channel utils.ChannelConfig) error {
+	requests, err := b.repo.ListRequests(storage.RequestQuery{
+		Channel:     channel.ID,
+		Start:       time.Now().Add(-reminderLookback),
+		Beaconed:    true,
+		NotResolved: true,
+		Filter:      b.countedIn(channel.ID),
+	})
+	if err != nil {
+		return err
+	}
+
+	maxReminders := channel.Reminders.MaxReminders
+	if maxReminders <= 0 {
+		maxReminders = utils.DefaultMaxReminders
+	}
+	now := time.Now()
+	for i := range requests {
+		request := &requests[i]
+		if request.Reminders >= maxReminders {
+			continue
+		}
+		if b.dueAt(lastActivity(request), channel.Reminders.IdleAfter).After(now) {
+			continue
+		}
+		if err := b.remind(channel, request, maxReminders, now); err != nil {
+			log.Printf("Failed to remind about request %s: %v", request.MessageTS, err)
+		}
+	}
+	return nil
+}
+
+// lastActivity returns when the request last moved: got the beacon, was
+// acked, got a reaction or a reply, or was reminded about.
+func lastActivity(request *storage.Request) time.Time {
+	last := request.PostedAt
+	for _, at := range []*time.Time{request.BeaconAt, request.AckedAt, request.ActiveAt, request.RemindedAt} {
+		if at != nil && at.After(last) {
+			last = *at
+		}
+	}
+	return last
+}
+
+// markActive records activity on the request at the given time, unless
+// later activity is recorded already.
+func markActive(request *storage.Request, at time.Time) {
+	if request.ActiveAt == nil || at.After(*request.ActiveAt) {
+		request.ActiveAt = &at
+	}
+}
+
+// trackReply records a thread reply as activity on the request of the
+// thread, if the thread is a request.
+func (b *Bot) trackReply(channelID, threadTS, replyTS string) error {
+	at, err := parseTimestamp(replyTS)
+	if err != nil {
+		return err
+	}
+
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+
+	request, err := b.repo.GetRequest(channelID, threadTS)
+	if errors.Is(err, storage.ErrRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	markActive(request, at)
+	return b.repo.SaveRequest(request)
+}
+
+// remind posts a reminder in the request thread, optionally DMs the assignee
+// and records the reminder.
+func (b *Bot) remind(channel utils.ChannelConfig, request *storage.Request, maxReminders int, now time.Time) error {
+	text := fmt.Sprintf("⏰ This request is still open, nothing happened for %s. Reminder %d of %d.",
+		formatDuration(now.Sub(lastActivity(request))), request.Reminders+1, maxReminders)
+	if request.Assignee != "" {
+		text = fmt.Sprintf("<@%s> %s", request.Assignee, text)
+	}
+	_, _, err := b.slackClient.PostMessageContext(b.ctx, channel.ID, slack.MsgOptionText(text, false), slack.MsgOptionTS(request.MessageTS))
+	if err != nil {
+		return fmt.Errorf("failed to post reminder: %w", err)
+	}
+
+	if channel.Reminders.DMAssignee && request.Assignee != "" {
+		dm := fmt.Sprintf("⏰ <%s|A request> in <#%s> assigned to you is still open.", request.Permalink, channel.ID)
+		if err := b.postDM(request.Assignee, dm); err != nil {
+			// the thread reminder is out already, so it still counts
+			log.Printf("Failed to send reminder to %s: %v", request.Assignee, err)
+		}
+	}
+	return b.recordReminder(channel.ID, request.MessageTS, now)
+}
+
+// recordReminder counts a reminder on the stored request, which may have
+// changed since it was listed.
+func (b *Bot) recordReminder(channelID, messageTS string, at time.Time) error {
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+
+	request, err := b.repo.GetRequest(channelID, messageTS)
+	if errors.Is(err, storage.ErrRequestNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	request.Reminders++
+	request.RemindedAt = &at
+	return b.repo.SaveRequest(request)
+}
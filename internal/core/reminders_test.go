@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemindStaleRequests(t *testing.T) {
+	config := newTestConfig()
+	config.Channels[0].Reminders.IdleAfter = time.Hour
+	config.Channels[0].Reminders.MaxReminders = 2
+	bot, client := newTestBot(t, config)
+
+	now := time.Now()
+	stale := fmt.Sprintf("%d.000100", now.Add(-5*time.Hour).Unix())
+	active := fmt.Sprintf("%d.000100", now.Add(-4*time.Hour).Unix())
+	resolved := fmt.Sprintf("%d.000100", now.Add(-3*time.Hour).Unix())
+	for _, message := range []slack.Message{
+		testMessage(stale, "", "U9", testReaction("eyes", "U1")),
+		testMessage(active, "", "U9", testReaction("eyes", "U1")),
+		testMessage(resolved, "", "U9", testReaction("eyes", "U1"), testReaction("white_check_mark", "U2")),
+	} {
+		_, _, err := bot.recordMessage("C123", message, message.Reactions, true, "")
+		assert.NoError(t, err)
+	}
+	// stages found by the sync are dated to it, so the requests are dated back by hand
+	idleSince := func(messageTS string, idle time.Duration) {
+		request, err := bot.repo.GetRequest("C123", messageTS)
+		assert.NoError(t, err)
+		at := now.Add(-idle)
+		request.BeaconAt, request.ActiveAt = &at, &at
+		if request.RemindedAt != nil {
+			request.RemindedAt = &at
+		}
+		assert.NoError(t, bot.repo.SaveRequest(request))
+	}
+	idleSince(stale, 2*time.Hour)
+	idleSince(active, 10*time.Minute)
+	idleSince(resolved, 2*time.Hour)
+
+	reminders := func(messageTS string) int {
+		request, err := bot.repo.GetRequest("C123", messageTS)
+		assert.NoError(t, err)
+		return request.Reminders
+	}
+
+	client.EXPECT().PostMessageContext(gomock.Any(), "C123", gomock.Any(), gomock.Any()).Return("", "", nil)
+	assert.NoError(t, bot.remindStaleRequests(config.Channels[0]))
+	assert.Equal(t, 1, reminders(stale), "Idle requests should be reminded about")
+	assert.Equal(t, 0, reminders(active), "Active requests should not be reminded about")
+	assert.Equal(t, 0, reminders(resolved), "Resolved requests should not be reminded about")
+
+	// the reminder counts as activity
+	assert.NoError(t, bot.remindStaleRequests(config.Channels[0]))
+	assert.Equal(t, 1, reminders(stale))
+
+	idleSince(stale, 2*time.Hour)
+	client.EXPECT().PostMessageContext(gomock.Any(), "C123", gomock.Any(), gomock.Any()).Return("", "", nil)
+	assert.NoError(t, bot.remindStaleRequests(config.Channels[0]))
+	assert.Equal(t, 2, reminders(stale))
+
+	idleSince(stale, 2*time.Hour)
+	assert.NoError(t, bot.remindStaleRequests(config.Channels[0]))
+	assert.Equal(t, 2, reminders(stale), "Requests should get at most max_reminders reminders")
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/slack-go/slack"
+)
+
+// unmappedReactionsLimit is how many reactions the unmapped reactions report lists.
+const unmappedReactionsLimit = 10
+
+// unmappedReaction is a reaction no rule matches, as used across requests.
+type unmappedReaction struct {
+	name      string
+	reactions int
+	requests  int
+}
+
+func (b *Bot) handleUnmappedReactionsReport(callback slack.InteractionCallback) error {
+	channelID, startDate, endDate, err := b.statsInterval(callback)
+	if err != nil {
+		return err
+	}
+	return b.sendUnmappedReactionsReport(channelID, startDate, endDate, callback.User.ID)
+}
+
+// sendUnmappedReactionsReport sends the user the reactions used on counted
+// requests of the channel that no rule matches, most used first, to help
+// write new rules.
+func (b *Bot) sendUnmappedReactionsReport(channelID string, startDate, endDate time.Time, userID string) error {
+	requests, err := b.repo.ListRequests(storage.RequestQuery{
+		Channel: channelID,
+		Start:   startDate,
+		End:     endDate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch requests: %w", err)
+	}
+
+	usage := make(map[string]*unmappedReaction)
+	for i := range requests {
+		request := &requests[i]
+		if !b.statsProcessor.ShouldCountRequest(channelID, request) {
+			continue
+		}
+		for name, count := range b.statsProcessor.UnmappedReactions(channelID, request) {
+			if usage[name] == nil {
+				usage[name] = &unmappedReaction{name: name}
+			}
+			usage[name].reactions += count
+			usage[name].requests++
+		}
+	}
+
+	unmapped := make([]*unmappedReaction, 0, len(usage))
+	for _, reaction := range usage {
+		unmapped = append(unmapped, reaction)
+	}
+	sort.Slice(unmapped, func(i, j int) bool {
+		if unmapped[i].reactions != unmapped[j].reactions {
+			return unmapped[i].reactions > unmapped[j].reactions
+		}
+		return unmapped[i].name < unmapped[j].name
+	})
+
+	period := fmt.Sprintf("from %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if len(unmapped) == 0 {
+		return b.postDM(userID, fmt.Sprintf("🎉 Every reaction in <#%s> %s is covered by a rule.", channelID, period))
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "🧐 Top unmapped reactions in <#%s> %s:\n", channelID, period)
+	for i, reaction := range unmapped[:min(len(unmapped), unmappedReactionsLimit)] {
+		fmt.Fprintf(&report, "%d. :%s: `%s` — %d reactions on %d requests\n", i+1, reaction.name, reaction.name, reaction.reactions, reaction.requests)
+	}
+	return b.postDM(userID, report.String())
+}
+
+func (b *Bot) handleResponseTimesReport(callback slack.InteractionCallback) error {
+	channelID, startDate, endDate, err := b.statsInterval(callback)
+	if err != nil {
+		return err
+	}
+	return b.sendResponseTimesReport(channelID, startDate, endDate, callback.User.ID)
+}
+
+// sendResponseTimesReport sends the user the median and p90 categorization,
+// response and resolution times and the SLA breaches per category of the
+// channel, in business time if business hours are configured.
+func (b *Bot) sendResponseTimesReport(channelID string, startDate, endDate time.Time, userID string) error {
+	stats, err := b.repo.GetLifecycleStats(storage.RequestQuery{
+		Channel: channelID,
+		Start:   startDate,
+		End:     endDate,
+		Filter:  b.countedIn(channelID),
+	}, b.elapsed())
+	if err != nil {
+		return fmt.Errorf("failed to fetch response times: %w", err)
+	}
+	breaches, err := b.repo.ListSLABreaches(storage.SLABreachQuery{
+		Channel: channelID,
+		Start:   startDate,
+		End:     endDate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch SLA breaches: %w", err)
+	}
+	breachCounts := make(map[string]int)
+	for _, breach := range breaches {
+		breachCounts[breach.Category]++
+	}
+
+	period := fmt.Sprintf("from %s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if len(stats) == 0 {
+		return b.postDM(userID, fmt.Sprintf("📉 No requests in <#%s> %s.", channelID, period))
+	}
+
+	var report strings.Builder
+	clock := "wall clock time"
+	if b.businessHours != nil {
+		clock = fmt.Sprintf("business hours, %s", b.businessHours.Location())
+	}
+	fmt.Fprintf(&report, "⏱️ Response times in <#%s> %s (%s):\n", channelID, period, clock)
+	for _, stat := range stats {
+		fmt.Fprintf(&report, "• *%s*: %d requests", stat.Category, stat.Requests)
+		if stat.Categorized > 0 {
+			fmt.Fprintf(&report, ", %d categorized (median %s, p90 %s)",
+				stat.Categorized, formatDuration(stat.MedianCategorization), formatDuration(stat.P90Categorization))
+		}
+		if stat.Acked > 0 {
+			fmt.Fprintf(&report, ", %d acked (median %s, p90 %s)",
+				stat.Acked, formatDuration(stat.MedianResponse), formatDuration(stat.P90Response))
+		}
+		if stat.Resolved > 0 {
+			fmt.Fprintf(&report, ", %d resolved (median %s, p90 %s)",
+				stat.Resolved, formatDuration(stat.MedianResolution), formatDuration(stat.P90Resolution))
+		}
+		if breachCounts[stat.Category] > 0 {
+			fmt.Fprintf(&report, ", %d SLA breaches", breachCounts[stat.Category])
+		}
+		report.WriteString("\n")
+	}
+	return b.postDM(userID, report.String())
+}
+
+// formatDuration formats a duration for reports, e.g. "45m", "2h5m" or "3d4h".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
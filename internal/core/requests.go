@@ -0,0 +1,408 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// reactionChange is a reaction added to or removed from a message.
+type reactionChange struct {
+	Item     slackevents.Item
+	Reaction string
+	User     string // user who reacted
+	ItemUser string // author of the reacted message
+	ReplyTS  string // thread reply the reaction is on, empty if it's on the request message
+	At       time.Time
+	Added    bool
+}
+
+// trackReaction applies a live reaction change to the request of the reacted
+// message and updates the stats of its day by the difference it makes. A
+// request that just got its beacon is assigned to whoever is on call, the
+// assignee is announced once the request is saved.
+func (b *Bot) trackReaction(change reactionChange) error {
+	channelID, messageTS := change.Item.Channel, change.Item.Timestamp
+	date, err := messageDate(messageTS)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := b.prefetchMessage(channelID, messageTS)
+	if err != nil {
+		return err
+	}
+
+	b.requestsMu.Lock()
+	request, assigned, err := b.applyReactionChange(change, date, snapshot)
+	b.requestsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	if assigned {
+		b.announceAssignee(channelID, request)
+	}
+	return nil
+}
+
+// applyReactionChange applies the reaction change to the request and the
+// stats. It reports whether the request was assigned. The caller must hold
+// requestsMu.
+func (b *Bot) applyReactionChange(change reactionChange, date time.Time, snapshot *messageSnapshot) (*storage.Request, bool, error) {
+	channelID, messageTS := change.Item.Channel, change.Item.Timestamp
+	before := make(map[string]storage.CategoryStats)
+	request, stored, err := b.loadRequest(channelID, messageTS, change.ItemUser, snapshot)
+	if err != nil {
+		return nil, false, err
+	}
+	state := b.requestState(channelID, request, stored)
+	if stored {
+		before = b.statsProcessor.RequestStats(channelID, request)
+	}
+	// a new request starts from the current state of the message, which already includes the change,
+	// and so may a request stored from a snapshot taken since
+	if stored && applyReactor(request, change.Reaction, reactorID(change.User, change.ReplyTS), change.Added) {
+		if request.Reactions == nil {
+			request.Reactions = make(map[string]int)
+		}
+		reaction := utils.ResolveReactionName(b.config, change.Reaction)
+		if change.Added {
+			request.Reactions[reaction]++
+		} else {
+			request.Reactions[reaction]--
+		}
+		if request.Reactions[reaction] <= 0 {
+			delete(request.Reactions, reaction)
+		}
+	}
+
+	beaconed := request.BeaconAt != nil
+	markActive(request, change.At)
+	b.statsProcessor.Categorize(channelID, request)
+	b.updateLifecycle(channelID, request, &change)
+	if !stored {
+		// the message may have reached other stages before the bot saw it
+		b.syncLifecycle(channelID, request, change.At)
+	}
+	assigned := !beaconed && request.BeaconAt != nil && b.assignOnCall(channelID, request, change.At)
+	if err := b.repo.SaveRequest(request); err != nil {
+		return nil, false, err
+	}
+	b.emitRequestChanges(channelID, request, state)
+	return request, assigned, b.applyStatsDiff(channelID, date, before, b.statsProcessor.RequestStats(channelID, request))
+}
+
+// messageSnapshot is the current state of a message, which the request of
+// a message that isn't stored yet is built from.
+type messageSnapshot struct {
+	message   slack.Message
+	reactions []slack.ItemReaction
+	permalink string
+}
+
+// prefetchMessage fetches the current state of the message unless its
+// request is already stored, in which case it returns nil. It talks to
+// Slack, so it's called before taking requestsMu.
+func (b *Bot) prefetchMessage(channelID, messageTS string) (*messageSnapshot, error) {
+	_, err := b.repo.GetRequest(channelID, messageTS)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, storage.ErrRequestNotFound) {
+		return nil, err
+	}
+
+	message, reactions, err := b.currentMessage(channelID, messageTS)
+	if err != nil {
+		return nil, err
+	}
+	return &messageSnapshot{
+		message:   message,
+		reactions: reactions,
+		permalink: b.permalink(channelID, messageTS),
+	}, nil
+}
+
+// loadRequest returns the stored request of the message, or a new one built
+// from the prefetched snapshot of the message. It reports whether it was
+// stored. The caller must hold requestsMu.
+func (b *Bot) loadRequest(channelID, messageTS, author string, snapshot *messageSnapshot) (*storage.Request, bool, error) {
+	request, err := b.repo.GetRequest(channelID, messageTS)
+	if err == nil {
+		return request, true, nil
+	}
+	if !errors.Is(err, storage.ErrRequestNotFound) {
+		return nil, false, err
+	}
+	if snapshot == nil {
+		// requests are never deleted, so a stored request can't be missing now
+		return nil, false, fmt.Errorf("request %s in %s was not prefetched", messageTS, channelID)
+	}
+
+	request, err = b.newRequest(channelID, messageTS, author, snapshot.permalink)
+	if err != nil {
+		return nil, false, err
+	}
+	if request.Author == "" {
+		request.Author = snapshot.message.User
+	}
+	request.Text = snapshot.message.Text
+	request.Reactions = b.statsProcessor.reactionCounts(snapshot.reactions)
+	request.Reactors = b.statsProcessor.reactors(snapshot.reactions)
+	return request, false, nil
+}
+
+// reactorID identifies who added a reaction to which message of a request:
+// the user for the request message, the user and the reply for replies.
+func reactorID(user, replyTS string) string {
+	if replyTS == "" {
+		return user
+	}
+	return user + "@" + replyTS
+}
+
+// reactorUser returns the user of a reactor ID.
+func reactorUser(reactor string) string {
+	user, _, _ := strings.Cut(reactor, "@")
+	return user
+}
+
+// applyReactor records that the reactor added or removed the reaction and
+// reports whether that's news to the request. A snapshot taken before the
+// event may already include it, and events can be delivered more than once.
+// Requests stored before reactors were tracked take every change as news.
+func applyReactor(request *storage.Request, reaction, reactor string, added bool) bool {
+	if request.Reactors == nil {
+		return true
+	}
+	reactors := request.Reactors[reaction]
+	i := slices.Index(reactors, reactor)
+	if added == (i >= 0) {
+		return false
+	}
+	if added {
+		request.Reactors[reaction] = append(reactors, reactor)
+		return true
+	}
+	if reactors = slices.Delete(reactors, i, i+1); len(reactors) > 0 {
+		request.Reactors[reaction] = reactors
+	} else {
+		delete(request.Reactors, reaction)
+	}
+	return true
+}
+
+// recordMessage stores the request of a message fetched from the channel
+// history, the given reactions replace the stored ones. Messages that don't
+// pass the beacon mode are only updated if they are already stored. The
+// permalink is used for new requests. An open request whose beacon showed up
+// since it was last seen is assigned to whoever is on call now, since the
+// time the beacon was added is unknown. Only requests that were already
+// stored emit webhook events, so a sync or backfill of old history doesn't
+// flood the webhooks. It returns the saved request, nil if it wasn't saved,
+// and whether it was assigned. The caller must hold requestsMu.
+func (b *Bot) recordMessage(channelID string, message slack.Message, reactions []slack.ItemReaction, passes bool, permalink string) (*storage.Request, bool, error) {
+	stored := true
+	request, err := b.repo.GetRequest(channelID, message.Timestamp)
+	if errors.Is(err, storage.ErrRequestNotFound) {
+		if !passes {
+			return nil, false, nil
+		}
+		stored = false
+		request, err = b.newRequest(channelID, message.Timestamp, message.User, permalink)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	state := b.requestState(channelID, request, stored)
+
+	if message.User != "" {
+		request.Author = message.User
+	}
+	request.Text = message.Text
+	request.Reactions = b.statsProcessor.reactionCounts(reactions)
+	request.Reactors = b.statsProcessor.reactors(reactions)
+	if latestReply, err := parseTimestamp(message.LatestReply); err == nil {
+		markActive(request, latestReply)
+	}
+	beaconed := request.BeaconAt != nil
+	b.statsProcessor.Categorize(channelID, request)
+	b.updateLifecycle(channelID, request, nil)
+	assigned := !beaconed && request.BeaconAt != nil && request.ResolvedAt == nil &&
+		b.assignOnCall(channelID, request, *request.BeaconAt)
+	if err := b.repo.SaveRequest(request); err != nil {
+		return nil, false, err
+	}
+	if stored {
+		b.emitRequestChanges(channelID, request, state)
+	}
+	return request, assigned, nil
+}
+
+// updateLifecycle moves the request along its lifecycle after its reactions
+// changed. Only live reaction events tell when a reaction was added. History
+// only tells which reactions are there, so stages reached while the bot
+// wasn't watching are dated to the sync and marked as synced. A request whose
+// resolution reaction is gone is open again.
+func (b *Bot) updateLifecycle(channelID string, request *storage.Request, change *reactionChange) {
+	resolved := utils.GetResolvedReaction(b.config, channelID)
+	if request.ResolvedAt != nil && (resolved == "" || request.Reactions[resolved] == 0) {
+		request.ResolvedAt = nil
+		request.ResolutionSynced = false
+	}
+	if change == nil {
+		b.syncLifecycle(channelID, request, time.Now())
+		return
+	}
+	if !change.Added {
+		return
+	}
+
+	at := change.At
+	reaction := utils.ResolveReactionName(b.config, change.Reaction)
+	if request.FirstReactionAt == nil {
+		request.FirstReactionAt = &at
+	}
+	if _, matched := utils.GetReactionRuleMatch(b.config, channelID, reaction); request.CategorizedAt == nil && matched {
+		request.CategorizedAt = &at
+	}
+	if request.BeaconAt == nil && reaction == utils.GetControllingReaction(b.config, channelID) {
+		request.BeaconAt = &at
+	}
+	if request.AckedAt == nil && reaction != "" && reaction == utils.GetAckReaction(b.config, channelID) {
+		request.AckedAt = &at
+	}
+	if request.ResolvedAt == nil && reaction != "" && reaction == resolved {
+		request.ResolvedAt = &at
+	}
+}
+
+// syncLifecycle sets the lifecycle stages of the request its reactions show
+// but no live event recorded, e.g. a request resolved while the bot was down.
+func (b *Bot) syncLifecycle(channelID string, request *storage.Request, now time.Time) {
+	has := func(reaction string) bool {
+		return reaction != "" && request.Reactions[reaction] > 0
+	}
+	if request.BeaconAt == nil && has(utils.GetControllingReaction(b.config, channelID)) {
+		request.BeaconAt = &now
+	}
+	if request.AckedAt == nil && has(utils.GetAckReaction(b.config, channelID)) {
+		request.AckedAt = &now
+		request.AckSynced = true
+	}
+	if request.ResolvedAt == nil && has(utils.GetResolvedReaction(b.config, channelID)) {
+		request.ResolvedAt = &now
+		request.ResolutionSynced = true
+	}
+}
+
+// countedIn returns a request query filter that accepts the requests passing
+// the beacon mode of the channel. Every reacted message is stored, so
+// reports and checks over stored requests have to filter them.
+func (b *Bot) countedIn(channelID string) func(*storage.Request) bool {
+	return func(request *storage.Request) bool {
+		return b.statsProcessor.ShouldCountRequest(channelID, request)
+	}
+}
+
+// applyStatsDiff updates the stats of the day by the difference between
+// what a request counted before and after a change.
+func (b *Bot) applyStatsDiff(channelID string, date time.Time, before, after map[string]storage.CategoryStats) error {
+	increments := make(map[string]storage.CategoryStats)
+	decrements := make(map[string]storage.CategoryStats)
+	for _, category := range changedCategories(before, after) {
+		requests := after[category].Requests - before[category].Requests
+		reactions := after[category].Reactions - before[category].Reactions
+		if requests > 0 || reactions > 0 {
+			increments[category] = storage.CategoryStats{Requests: max(requests, 0), Reactions: max(reactions, 0)}
+		}
+		if requests < 0 || reactions < 0 {
+			decrements[category] = storage.CategoryStats{Requests: max(-requests, 0), Reactions: max(-reactions, 0)}
+		}
+	}
+
+	if len(increments) > 0 {
+		if err := b.repo.IncrementStats(channelID, date, increments); err != nil {
+			return err
+		}
+	}
+	if len(decrements) > 0 {
+		return b.repo.DecrementStats(channelID, date, decrements)
+	}
+	return nil
+}
+
+// changedCategories returns the categories present before or after a change.
+func changedCategories(before, after map[string]storage.CategoryStats) []string {
+	var categories []string
+	for category := range before {
+		categories = append(categories, category)
+	}
+	for category := range after {
+		if _, exists := before[category]; !exists {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// newRequest prepares a request for a message that is not stored yet.
+func (b *Bot) newRequest(channelID, messageTS, author, permalink string) (*storage.Request, error) {
+	postedAt, err := parseTimestamp(messageTS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.Request{
+		Channel:   channelID,
+		MessageTS: messageTS,
+		Author:    author,
+		Permalink: permalink,
+		PostedAt:  postedAt,
+	}, nil
+}
+
+// permalink returns the link to the message, empty if it can't be fetched,
+// since a request is still worth storing without a link.
+func (b *Bot) permalink(channelID, messageTS string) string {
+	permalink, err := b.slackClient.GetPermalinkContext(b.ctx, &slack.PermalinkParameters{
+		Channel: channelID,
+		Ts:      messageTS,
+	})
+	if err != nil {
+		log.Printf("Failed to get permalink for message %s: %v", messageTS, err)
+	}
+	return permalink
+}
+
+// parseTimestamp converts a Slack timestamp ("1738144800.000100") into time.
+// The fraction is optional and may have any number of digits.
+func parseTimestamp(timestamp string) (time.Time, error) {
+	secondsPart, fraction, _ := strings.Cut(timestamp, ".")
+	seconds, err := strconv.ParseInt(secondsPart, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid slack timestamp %q: %w", timestamp, err)
+	}
+
+	var nanos int64
+	if fraction != "" {
+		// "0001" is 100µs, so the fraction is scaled to nanoseconds by its digits
+		if len(fraction) > 9 {
+			fraction = fraction[:9]
+		}
+		nanos, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil || nanos < 0 {
+			return time.Time{}, fmt.Errorf("invalid slack timestamp %q", timestamp)
+		}
+	}
+	return time.Unix(seconds, nanos), nil
+}
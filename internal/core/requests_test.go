@@ -0,0 +1,109 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artemlive/tars/pkg/webhook"
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackReaction_SnapshotIncludesEvent(t *testing.T) {
+	const messageTS = "1738144800.000100"
+	bot, client := newTestBot(t, newTestConfig())
+
+	// the snapshot of the new request already has the eyes, whose event is handled later,
+	// the thread lookup and the snapshot each fetch the message
+	message := testMessage(messageTS, "", "U9", testReaction("bug", "U1"), testReaction("eyes", "U2"))
+	client.EXPECT().FetchReplies(gomock.Any(), "C123", messageTS).Return([]slack.Message{message}, nil).Times(2)
+	client.EXPECT().GetPermalinkContext(gomock.Any(), gomock.Any()).Return("https://example.slack.com/p1", nil)
+
+	steps := []struct {
+		name     string
+		change   reactionChange
+		expected map[string]int
+	}{
+		{
+			name:     "new request starts from the snapshot",
+			change:   testReactionChange(messageTS, "bug", "U1", true),
+			expected: map[string]int{"bug": 1, "eyes": 1},
+		},
+		{
+			name:     "event the snapshot included",
+			change:   testReactionChange(messageTS, "eyes", "U2", true),
+			expected: map[string]int{"bug": 1, "eyes": 1},
+		},
+		{
+			name:     "redelivered event",
+			change:   testReactionChange(messageTS, "eyes", "U2", true),
+			expected: map[string]int{"bug": 1, "eyes": 1},
+		},
+		{
+			name:     "new reaction",
+			change:   testReactionChange(messageTS, "bug", "U3", true),
+			expected: map[string]int{"bug": 2, "eyes": 1},
+		},
+		{
+			name:     "removed reaction",
+			change:   testReactionChange(messageTS, "bug", "U1", false),
+			expected: map[string]int{"bug": 1, "eyes": 1},
+		},
+		{
+			name:     "reaction removed twice",
+			change:   testReactionChange(messageTS, "bug", "U1", false),
+			expected: map[string]int{"bug": 1, "eyes": 1},
+		},
+	}
+
+	for _, step := range steps {
+		assert.NoError(t, bot.applyReaction(step.change), step.name)
+		request, err := bot.repo.GetRequest("C123", messageTS)
+		assert.NoError(t, err, step.name)
+		assert.Equal(t, step.expected, request.Reactions, step.name)
+	}
+
+	stats, err := bot.repo.GetAggregatedStats("C123", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "Infra bug", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Count)
+	assert.Equal(t, 1, stats[0].Requests)
+}
+
+func TestRecordMessage_Webhooks(t *testing.T) {
+	const messageTS = "1738144800.000100"
+	var mu sync.Mutex
+	var events []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, r.Header.Get(webhook.HeaderEvent))
+	}))
+	defer server.Close()
+
+	bot, _ := newTestBot(t, newTestConfig())
+	bot.webhooks = webhook.NewDispatcher([]webhook.Endpoint{{URL: server.URL}}, nil)
+
+	// a request the sync sees for the first time is history, not news
+	reactions := []slack.ItemReaction{testReaction("bug", "U1"), testReaction("eyes", "U2")}
+	request, _, err := bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+	assert.NotNil(t, request)
+
+	// synced again without changes
+	_, _, err = bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+
+	reactions = append(reactions, testReaction("white_check_mark", "U3"))
+	_, _, err = bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+	bot.webhooks.Close()
+
+	assert.Contains(t, events, "request.resolved", "Changes to stored requests should be posted")
+	assert.NotContains(t, events, "request.categorized", "New requests from the sync should not be posted")
+}
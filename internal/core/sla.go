@@ -0,0 +1,212 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+)
+
+const (
+	slaCheckInterval = time.Minute        // how often open requests are checked against their SLA
+	slaLookback      = 7 * 24 * time.Hour // how long after its largest target a request is still checked
+	slaAlertWindow   = time.Hour          // breaches due earlier are recorded without an alert, e.g. after a backfill
+)
+
+// runSLAMonitor periodically checks the open requests of the channels with
+// SLA targets until the bot context is canceled.
+func (b *Bot) runSLAMonitor() {
+	ticker := time.NewTicker(slaCheckInterval)
+	defer ticker.Stop()
+	for {
+		for _, channel := range b.config.Channels {
+			if len(channel.SLA.Targets) == 0 {
+				continue
+			}
+			if b.ctx.Err() != nil {
+				return
+			}
+			if err := b.checkSLAs(channel.ID); err != nil {
+				log.Printf("Failed to check SLAs of channel %s: %v", channel.ID, err)
+			}
+		}
+
+		select {
+		case <-b.ctx.Done():
+			log.Println("SLA monitor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkSLAs records the open requests of the channel that missed their SLA
+// targets and posts the alerts that weren't posted yet.
+func (b *Bot) checkSLAs(channelID string) error {
+	now := time.Now()
+	requests, err := b.repo.ListRequests(storage.RequestQuery{
+		Channel:     channelID,
+		Start:       now.Add(-b.slaLookback(channelID)),
+		NotResolved: true,
+		Filter:      b.countedIn(channelID),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range requests {
+		request := &requests[i]
+		for _, breach := range b.slaBreaches(channelID, request, now) {
+			if err := b.recordSLABreach(channelID, request, breach, now); err != nil {
+				log.Printf("Failed to record SLA breach of request %s: %v", request.MessageTS, err)
+			}
+		}
+	}
+	return b.alertSLABreaches(channelID, requests, now)
+}
+
+// slaLookback returns how far back requests of the channel are checked: the
+// largest target of the channel, with room for downtime and closed hours.
+func (b *Bot) slaLookback(channelID string) time.Duration {
+	var largest time.Duration
+	if channel, ok := utils.GetChannelConfig(b.config, channelID); ok {
+		for _, target := range channel.SLA.Targets {
+			largest = max(largest, target.AckWithin, target.ResolveWithin)
+		}
+	}
+	return largest + slaLookback
+}
+
+// slaBreaches returns the SLA targets the request missed. With several
+// categories the strictest target counts. Targets count business time if
+// business hours are configured.
+func (b *Bot) slaBreaches(channelID string, request *storage.Request, now time.Time) []storage.SLABreach {
+	checkAck := request.AckedAt == nil && utils.GetAckReaction(b.config, channelID) != ""
+	checkResolve := request.ResolvedAt == nil && utils.GetResolvedReaction(b.config, channelID) != ""
+
+	strictest := make(map[string]storage.SLABreach)
+	consider := func(kind, category string, within time.Duration) {
+		if within <= 0 {
+			return
+		}
+		dueAt := b.dueAt(request.PostedAt, within)
+		if current, exists := strictest[kind]; !exists || dueAt.Before(current.DueAt) {
+			strictest[kind] = storage.SLABreach{
+				RequestID: request.ID,
+				Kind:      kind,
+				Channel:   channelID,
+				Category:  category,
+				Target:    within,
+				DueAt:     dueAt,
+			}
+		}
+	}
+	categories := request.CategoryNames()
+	if len(categories) == 0 {
+		// only the target for every category applies to requests without one
+		categories = []string{""}
+	}
+	for _, category := range categories {
+		target, ok := utils.GetSLATarget(b.config, channelID, category)
+		if !ok {
+			continue
+		}
+		if checkAck {
+			consider(storage.SLAKindAck, category, target.AckWithin)
+		}
+		if checkResolve {
+			consider(storage.SLAKindResolve, category, target.ResolveWithin)
+		}
+	}
+
+	var breaches []storage.SLABreach
+	for _, kind := range []string{storage.SLAKindAck, storage.SLAKindResolve} {
+		if breach, exists := strictest[kind]; exists && now.After(breach.DueAt) {
+			breach.DetectedAt = now
+			breaches = append(breaches, breach)
+		}
+	}
+	return breaches
+}
+
+// recordSLABreach records the breach and, the first time it's seen, posts it
+// to the webhooks. Alerts are posted by alertSLABreaches.
+func (b *Bot) recordSLABreach(channelID string, request *storage.Request, breach storage.SLABreach, now time.Time) error {
+	created, err := b.repo.RecordSLABreach(&breach)
+	if err != nil || !created {
+		return err
+	}
+	log.Printf("Request %s in %s breached its %s SLA of %s", request.MessageTS, channelID, breach.Kind, breach.Target)
+	if breach.DueAt.Before(now.Add(-slaAlertWindow)) {
+		return nil
+	}
+	b.emit(eventSLABreached, slaBreachPayload{
+		Kind:     breach.Kind,
+		Category: breach.Category,
+		Target:   breach.Target.String(),
+		DueAt:    breach.DueAt,
+		Request:  b.requestPayload(channelID, request),
+	})
+	return nil
+}
+
+// alertSLABreaches posts the alerts of the breaches of the open requests that
+// weren't posted yet, so alerts that failed are retried on the next check.
+// Breaches due before the alert window are left without an alert.
+func (b *Bot) alertSLABreaches(channelID string, requests []storage.Request, now time.Time) error {
+	breaches, err := b.repo.ListSLABreaches(storage.SLABreachQuery{
+		Channel:     channelID,
+		Start:       now.Add(-slaAlertWindow),
+		NotNotified: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	open := make(map[uint]*storage.Request, len(requests))
+	for i := range requests {
+		open[requests[i].ID] = &requests[i]
+	}
+	for _, breach := range breaches {
+		request, exists := open[breach.RequestID]
+		// the request may have been handled since
+		if !exists || (breach.Kind == storage.SLAKindAck && request.AckedAt != nil) {
+			continue
+		}
+		if err := b.alertSLABreach(channelID, request, breach); err != nil {
+			log.Printf("Failed to alert SLA breach of request %s: %v", request.MessageTS, err)
+		}
+	}
+	return nil
+}
+
+// alertSLABreach posts the alert of the breach in the request thread or the
+// escalation channel.
+func (b *Bot) alertSLABreach(channelID string, request *storage.Request, breach storage.SLABreach) error {
+	var err error
+	action := "acked"
+	if breach.Kind == storage.SLAKindResolve {
+		action = "resolved"
+	}
+	category := breach.Category
+	if category == "" {
+		category = "uncategorized"
+	}
+	channel, _ := utils.GetChannelConfig(b.config, channelID)
+	if channel.SLA.EscalationChannel != "" {
+		text := fmt.Sprintf("⚠️ SLA breach in <#%s>: <%s|this *%s* request> wasn't %s within %s.",
+			channelID, request.Permalink, category, action, formatDuration(breach.Target))
+		_, _, err = b.slackClient.PostMessageContext(b.ctx, channel.SLA.EscalationChannel, slack.MsgOptionText(text, false))
+	} else {
+		text := fmt.Sprintf("⚠️ SLA breach: this *%s* request wasn't %s within %s.",
+			category, action, formatDuration(breach.Target))
+		_, _, err = b.slackClient.PostMessageContext(b.ctx, channelID, slack.MsgOptionText(text, false), slack.MsgOptionTS(request.MessageTS))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post SLA alert: %w", err)
+	}
+	return b.repo.MarkSLABreachNotified(breach.ID)
+}
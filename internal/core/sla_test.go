@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLABreaches_Uncategorized(t *testing.T) {
+	now := time.Date(2025, 1, 29, 12, 0, 0, 0, time.UTC)
+	request := &storage.Request{ID: 1, PostedAt: now.Add(-2 * time.Hour)}
+
+	tests := []struct {
+		name     string
+		targets  []utils.SLATarget
+		expected []storage.SLABreach
+	}{
+		{
+			name:    "the catch-all target applies",
+			targets: []utils.SLATarget{{AckWithin: time.Hour}, {Category: "Infra bug", AckWithin: 3 * time.Hour}},
+			expected: []storage.SLABreach{{RequestID: 1, Kind: storage.SLAKindAck, Channel: "C123",
+				Target: time.Hour, DueAt: now.Add(-time.Hour), DetectedAt: now}},
+		},
+		{
+			name:    "category targets don't apply",
+			targets: []utils.SLATarget{{Category: "Infra bug", AckWithin: time.Hour}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig()
+			config.Channels[0].SLA.Targets = tt.targets
+			bot, _ := newTestBot(t, config)
+			assert.Equal(t, tt.expected, bot.slaBreaches("C123", request, now))
+		})
+	}
+}
+
+func TestCheckSLAs(t *testing.T) {
+	config := newTestConfig()
+	config.Channels[0].SLA.Targets = []utils.SLATarget{{AckWithin: 10 * time.Minute}}
+	bot, client := newTestBot(t, config)
+
+	now := time.Now()
+	late := fmt.Sprintf("%d.000100", now.Add(-30*time.Minute).Unix())
+	// missed its target before the alert window, e.g. found by a backfill
+	old := fmt.Sprintf("%d.000100", now.Add(-3*time.Hour).Unix())
+	acked := fmt.Sprintf("%d.000100", now.Add(-20*time.Minute).Unix())
+	for _, message := range []slack.Message{
+		testMessage(late, "", "U9", testReaction("eyes", "U1")),
+		testMessage(old, "", "U9", testReaction("eyes", "U1")),
+		testMessage(acked, "", "U9", testReaction("eyes", "U1"), testReaction("raising_hand", "U2")),
+	} {
+		_, _, err := bot.recordMessage("C123", message, message.Reactions, true, "")
+		assert.NoError(t, err)
+	}
+
+	breaches := func() []storage.SLABreach {
+		breaches, err := bot.repo.ListSLABreaches(storage.SLABreachQuery{Channel: "C123"})
+		assert.NoError(t, err)
+		return breaches
+	}
+
+	// the alert fails to post
+	client.EXPECT().PostMessageContext(gomock.Any(), "C123", gomock.Any()).Return("", "", errors.New("channel_not_found"))
+	assert.NoError(t, bot.checkSLAs("C123"))
+	recorded := breaches()
+	assert.Len(t, recorded, 2, "Late requests should be recorded once each")
+	for _, breach := range recorded {
+		assert.Equal(t, storage.SLAKindAck, breach.Kind)
+		assert.False(t, breach.Notified)
+	}
+
+	// the next check retries the alert
+	client.EXPECT().PostMessageContext(gomock.Any(), "C123", gomock.Any()).Return("", "", nil)
+	assert.NoError(t, bot.checkSLAs("C123"))
+	// and there is nothing left to alert
+	assert.NoError(t, bot.checkSLAs("C123"))
+
+	recorded = breaches()
+	assert.Len(t, recorded, 2)
+	notified := make(map[uint]bool)
+	for _, breach := range recorded {
+		notified[breach.RequestID] = breach.Notified
+	}
+	lateRequest, err := bot.repo.GetRequest("C123", late)
+	assert.NoError(t, err)
+	oldRequest, err := bot.repo.GetRequest("C123", old)
+	assert.NoError(t, err)
+	assert.True(t, notified[lateRequest.ID], "The alert should be marked as posted")
+	assert.False(t, notified[oldRequest.ID], "Breaches before the alert window should not be alerted")
+}
@@ -2,29 +2,51 @@ package core
 
 import (
 	"log"
+	"slices"
+	"sort"
 
+	"github.com/artemlive/tars/pkg/storage"
 	"github.com/artemlive/tars/pkg/utils"
 	"github.com/slack-go/slack"
 )
 
 type StatsProcessor struct {
-	config *utils.Config
+	config      *utils.Config
+	ignoredUser string // user whose reactions don't count, the bot itself
 }
 
 func NewStatsProcessor(config *utils.Config) *StatsProcessor {
 	return &StatsProcessor{config: config}
 }
 
+// ShouldProcessMessage reports whether the message passes the beacon mode of the channel.
 func (sp *StatsProcessor) ShouldProcessMessage(channelID string, message slack.Message, beaconReaction string) bool {
-	if beaconReaction == "" {
+	return sp.passesBeacon(channelID, sp.reactionCounts(message.Reactions), message.Text, beaconReaction)
+}
+
+// ShouldCountRequest reports whether the stored request passes the beacon
+// mode of the channel. Requests someone categorized by hand always count.
+func (sp *StatsProcessor) ShouldCountRequest(channelID string, request *storage.Request) bool {
+	if request.ManualCategory != "" {
 		return true
 	}
-	for _, reaction := range message.Reactions {
-		if reaction.Name == beaconReaction {
+	return sp.passesBeacon(channelID, request.Reactions, request.Text, utils.GetControllingReaction(sp.config, channelID))
+}
+
+func (sp *StatsProcessor) passesBeacon(channelID string, reactions map[string]int, text, beaconReaction string) bool {
+	switch utils.GetBeaconMode(sp.config, channelID) {
+	case utils.BeaconModeDisabled:
+		return true
+	case utils.BeaconModeAnyReaction:
+		return len(reactions) > 0
+	case utils.BeaconModeText:
+		if len(utils.GetTextRuleMatches(sp.config, channelID, text)) > 0 {
 			return true
 		}
+		return reactions[utils.ResolveReactionName(sp.config, beaconReaction)] > 0
+	default:
+		return reactions[utils.ResolveReactionName(sp.config, beaconReaction)] > 0
 	}
-	return len(message.Reactions) > 0
 }
 
 func (sp *StatsProcessor) UpdateStats(channelID string, reactions []slack.ItemReaction, stats map[string]int) {
@@ -36,3 +58,229 @@ func (sp *StatsProcessor) UpdateStats(channelID string, reactions []slack.ItemRe
 		}
 	}
 }
+
+// categoryCandidate is a category matched by the rules of the channel.
+type categoryCandidate struct {
+	category string
+	count    int
+	priority int // highest priority of the matching rules
+	order    int // position of the first matching rule
+	source   string
+}
+
+// Categorize resolves the categories of the request from its reactions and
+// text, according to the category policy of the channel. A category picked
+// by a person overrides the rules.
+func (sp *StatsProcessor) Categorize(channelID string, request *storage.Request) {
+	stats := make(map[string]int)
+	sp.UpdateStats(channelID, itemReactions(request.Reactions), stats)
+
+	if request.ManualCategory != "" {
+		request.Categories = append(request.Categories[:0], storage.RequestCategory{
+			Category: request.ManualCategory,
+			Count:    stats[request.ManualCategory],
+			Source:   storage.CategorySourceManual,
+		})
+		return
+	}
+
+	candidates := make(map[string]*categoryCandidate)
+	consider := func(match utils.RuleMatch, count int, source string) {
+		candidate, exists := candidates[match.Category]
+		if !exists {
+			candidates[match.Category] = &categoryCandidate{
+				category: match.Category,
+				count:    count,
+				priority: match.Priority,
+				order:    match.Order,
+				source:   source,
+			}
+			return
+		}
+		candidate.priority = max(candidate.priority, match.Priority)
+		candidate.order = min(candidate.order, match.Order)
+	}
+	for _, reaction := range sortedKeys(request.Reactions) {
+		if match, found := utils.GetReactionRuleMatch(sp.config, channelID, reaction); found {
+			consider(match, stats[match.Category], storage.CategorySourceReaction)
+		}
+	}
+	for _, match := range utils.GetTextRuleMatches(sp.config, channelID, request.Text) {
+		consider(match, 0, storage.CategorySourceText)
+	}
+
+	request.Categories = request.Categories[:0]
+	for _, candidate := range resolveCategories(utils.GetCategoryPolicy(sp.config, channelID), candidates) {
+		request.Categories = append(request.Categories, storage.RequestCategory{
+			Category: candidate.category,
+			Count:    candidate.count,
+			Source:   candidate.source,
+		})
+	}
+
+	// requests no rule matched land in the uncategorized bucket, with the
+	// reactions nobody wrote a rule for yet
+	if len(request.Categories) == 0 && sp.config.Bot.UncategorizedCategory != "" {
+		count := 0
+		for _, reactions := range sp.UnmappedReactions(channelID, request) {
+			count += reactions
+		}
+		request.Categories = append(request.Categories, storage.RequestCategory{
+			Category: sp.config.Bot.UncategorizedCategory,
+			Count:    count,
+			Source:   storage.CategorySourceUncategorized,
+		})
+	}
+}
+
+// UnmappedReactions returns the reactions of the request that no rule of the
+// channel matches, except for the beacon and the lifecycle reactions.
+func (sp *StatsProcessor) UnmappedReactions(channelID string, request *storage.Request) map[string]int {
+	expected := map[string]bool{
+		utils.GetControllingReaction(sp.config, channelID): true,
+		utils.GetAckReaction(sp.config, channelID):         true,
+		utils.GetResolvedReaction(sp.config, channelID):    true,
+	}
+	unmapped := make(map[string]int)
+	for reaction, count := range request.Reactions {
+		if expected[reaction] {
+			continue
+		}
+		if _, found := utils.GetReactionRuleMatch(sp.config, channelID, reaction); !found {
+			unmapped[reaction] += count
+		}
+	}
+	return unmapped
+}
+
+// resolveCategories picks the categories the request lands in, sorted by name.
+func resolveCategories(policy string, candidates map[string]*categoryCandidate) []*categoryCandidate {
+	sorted := make([]*categoryCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		sorted = append(sorted, candidate)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].category < sorted[j].category })
+	if len(sorted) == 0 {
+		return sorted
+	}
+
+	// better reports whether a is preferred over b by the policy
+	var better func(a, b *categoryCandidate) bool
+	switch policy {
+	case utils.CategoryPolicyFirstByRuleOrder:
+		better = func(a, b *categoryCandidate) bool { return a.order < b.order }
+	case utils.CategoryPolicyHighestPriority:
+		better = func(a, b *categoryCandidate) bool {
+			if a.priority != b.priority {
+				return a.priority > b.priority
+			}
+			return a.order < b.order
+		}
+	case utils.CategoryPolicyMajorityVote:
+		better = func(a, b *categoryCandidate) bool {
+			if a.count != b.count {
+				return a.count > b.count
+			}
+			if a.priority != b.priority {
+				return a.priority > b.priority
+			}
+			return a.order < b.order
+		}
+	default:
+		return sorted
+	}
+
+	best := sorted[0]
+	for _, candidate := range sorted[1:] {
+		if better(candidate, best) {
+			best = candidate
+		}
+	}
+	return []*categoryCandidate{best}
+}
+
+// RequestStats returns what the request adds to the stats of its day: one
+// request and its reactions per category, nothing if the request doesn't
+// pass the beacon mode.
+func (sp *StatsProcessor) RequestStats(channelID string, request *storage.Request) map[string]storage.CategoryStats {
+	stats := make(map[string]storage.CategoryStats)
+	if !sp.ShouldCountRequest(channelID, request) {
+		return stats
+	}
+	for _, category := range request.Categories {
+		stats[category.Category] = storage.CategoryStats{Requests: 1, Reactions: category.Count}
+	}
+	return stats
+}
+
+// ReactionsComplete reports whether the reactions embedded in a message from
+// the history payload are complete. Slack truncates the users of popular
+// reactions, in which case reactions.get has to be used instead.
+func (sp *StatsProcessor) ReactionsComplete(message slack.Message) bool {
+	for _, reaction := range message.Reactions {
+		if len(reaction.Users) < reaction.Count {
+			return false
+		}
+	}
+	return true
+}
+
+// IgnoreUser leaves the reactions of the user out of the counts.
+func (sp *StatsProcessor) IgnoreUser(userID string) {
+	sp.ignoredUser = userID
+}
+
+// reactionCounts sums up the reactions by their resolved names, so skin tone
+// variants and aliases of an emoji count as one reaction. Reactions of the
+// ignored user are left out.
+func (sp *StatsProcessor) reactionCounts(reactions []slack.ItemReaction) map[string]int {
+	counts := make(map[string]int)
+	for _, reaction := range reactions {
+		count := reaction.Count
+		if sp.ignoredUser != "" && slices.ContainsFunc(reaction.Users, sp.ignored) {
+			count--
+		}
+		if count > 0 {
+			counts[utils.ResolveReactionName(sp.config, reaction.Name)] += count
+		}
+	}
+	return counts
+}
+
+// reactors returns who added each reaction, by the reaction names as Slack
+// sends them. The users of reactions on replies are reactor IDs already, see
+// replyReactions. Reactions of the ignored user are left out.
+func (sp *StatsProcessor) reactors(reactions []slack.ItemReaction) map[string][]string {
+	reactors := make(map[string][]string)
+	for _, reaction := range reactions {
+		for _, reactor := range reaction.Users {
+			if !sp.ignored(reactor) {
+				reactors[reaction.Name] = append(reactors[reaction.Name], reactor)
+			}
+		}
+	}
+	return reactors
+}
+
+// ignored reports whether the reactor is the ignored user.
+func (sp *StatsProcessor) ignored(reactor string) bool {
+	return sp.ignoredUser != "" && reactorUser(reactor) == sp.ignoredUser
+}
+
+// itemReactions converts reaction counts back into Slack reactions, sorted by name.
+func itemReactions(counts map[string]int) []slack.ItemReaction {
+	reactions := make([]slack.ItemReaction, 0, len(counts))
+	for _, name := range sortedKeys(counts) {
+		reactions = append(reactions, slack.ItemReaction{Name: name, Count: counts[name]})
+	}
+	return reactions
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCategories(t *testing.T) {
+	candidates := func() map[string]*categoryCandidate {
+		return map[string]*categoryCandidate{
+			"CI/CD":     {category: "CI/CD", count: 3, priority: 0, order: 2},
+			"Incident":  {category: "Incident", count: 1, priority: 10, order: 3},
+			"Infra bug": {category: "Infra bug", count: 3, priority: 0, order: 1},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		policy     string
+		candidates map[string]*categoryCandidate
+		expected   []string
+	}{
+		{
+			name:       "all counts every match, lower priorities too",
+			policy:     utils.CategoryPolicyAll,
+			candidates: candidates(),
+			expected:   []string{"CI/CD", "Incident", "Infra bug"},
+		},
+		{
+			name:       "unknown policy falls back to all",
+			policy:     "",
+			candidates: candidates(),
+			expected:   []string{"CI/CD", "Incident", "Infra bug"},
+		},
+		{
+			name:       "first by rule order",
+			policy:     utils.CategoryPolicyFirstByRuleOrder,
+			candidates: candidates(),
+			expected:   []string{"Infra bug"},
+		},
+		{
+			name:       "highest priority",
+			policy:     utils.CategoryPolicyHighestPriority,
+			candidates: candidates(),
+			expected:   []string{"Incident"},
+		},
+		{
+			name:   "highest priority tie goes to the first rule",
+			policy: utils.CategoryPolicyHighestPriority,
+			candidates: map[string]*categoryCandidate{
+				"CI/CD":     {category: "CI/CD", priority: 5, order: 2},
+				"Infra bug": {category: "Infra bug", priority: 5, order: 1},
+			},
+			expected: []string{"Infra bug"},
+		},
+		{
+			name:       "majority vote tie on reactions goes to the first rule",
+			policy:     utils.CategoryPolicyMajorityVote,
+			candidates: candidates(),
+			expected:   []string{"Infra bug"},
+		},
+		{
+			name:   "majority vote tie on reactions goes to the higher priority",
+			policy: utils.CategoryPolicyMajorityVote,
+			candidates: map[string]*categoryCandidate{
+				"CI/CD":    {category: "CI/CD", count: 2, priority: 0, order: 1},
+				"Incident": {category: "Incident", count: 2, priority: 10, order: 2},
+				"Network":  {category: "Network", count: 1, priority: 20, order: 3},
+			},
+			expected: []string{"Incident"},
+		},
+		{
+			name:       "no matches",
+			policy:     utils.CategoryPolicyMajorityVote,
+			candidates: map[string]*categoryCandidate{},
+			expected:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := resolveCategories(tt.policy, tt.candidates)
+			names := make([]string, 0, len(resolved))
+			for _, candidate := range resolved {
+				names = append(names, candidate.category)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func newTestStatsProcessor(t *testing.T) *StatsProcessor {
+	t.Helper()
+	config := &utils.Config{
+		Channels: []utils.ChannelConfig{{
+			ID:               "C123",
+			BeaconReaction:   ":eyes:",
+			AckReaction:      ":raising_hand:",
+			ResolvedReaction: ":white_check_mark:",
+			Rules: []utils.RuleConfig{
+				{Reaction: "bug", Category: "Infra bug"},
+				{Keywords: []string{"jenkins"}, Category: "CI/CD"},
+			},
+		}},
+	}
+	config.Bot.UncategorizedCategory = "Uncategorized"
+	assert.NoError(t, config.BuildReactionCache())
+	return NewStatsProcessor(config)
+}
+
+func TestCategorize_Uncategorized(t *testing.T) {
+	sp := newTestStatsProcessor(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		reactions map[string]int
+		expected  map[string]storage.CategoryStats
+	}{
+		{
+			name:      "matched requests aren't uncategorized",
+			reactions: map[string]int{"eyes": 1, "bug": 2, "tada": 1},
+			expected:  map[string]storage.CategoryStats{"Infra bug": {Requests: 1, Reactions: 2}},
+		},
+		{
+			name:      "text matches aren't uncategorized",
+			text:      "jenkins is down",
+			reactions: map[string]int{"eyes": 1},
+			expected:  map[string]storage.CategoryStats{"CI/CD": {Requests: 1}},
+		},
+		{
+			name:      "unmatched requests count unmapped reactions",
+			reactions: map[string]int{"eyes": 1, "raising_hand": 1, "tada": 2, "rocket": 1},
+			expected:  map[string]storage.CategoryStats{"Uncategorized": {Requests: 1, Reactions: 3}},
+		},
+		{
+			name:      "requests without the beacon don't count",
+			reactions: map[string]int{"tada": 2},
+			expected:  map[string]storage.CategoryStats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &storage.Request{Text: tt.text, Reactions: tt.reactions}
+			sp.Categorize("C123", request)
+			assert.Equal(t, tt.expected, sp.RequestStats("C123", request))
+		})
+	}
+}
+
+func TestUnmappedReactions(t *testing.T) {
+	sp := newTestStatsProcessor(t)
+
+	tests := []struct {
+		name      string
+		reactions map[string]int
+		expected  map[string]int
+	}{
+		{
+			name:      "beacon and lifecycle reactions are expected",
+			reactions: map[string]int{"eyes": 1, "raising_hand": 1, "white_check_mark": 1},
+			expected:  map[string]int{},
+		},
+		{
+			name:      "rule reactions are mapped",
+			reactions: map[string]int{"eyes": 1, "bug": 3, "tada": 2},
+			expected:  map[string]int{"tada": 2},
+		},
+		{
+			name:      "every unmapped reaction is counted",
+			reactions: map[string]int{"tada": 2, "rocket": 1},
+			expected:  map[string]int{"tada": 2, "rocket": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &storage.Request{Reactions: tt.reactions}
+			assert.Equal(t, tt.expected, sp.UnmappedReactions("C123", request))
+		})
+	}
+}
+
+func TestReactionCounts_IgnoredUser(t *testing.T) {
+	sp := newTestStatsProcessor(t)
+	sp.IgnoreUser("U0BOT")
+
+	counts := sp.reactionCounts([]slack.ItemReaction{
+		{Name: "bug", Count: 2, Users: []string{"U1", "U0BOT"}},
+		{Name: "eyes", Count: 1, Users: []string{"U0BOT"}},
+		{Name: "tada", Count: 1, Users: []string{"U2"}},
+	})
+	assert.Equal(t, map[string]int{"bug": 1, "tada": 1}, counts)
+}
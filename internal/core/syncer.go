@@ -0,0 +1,323 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+)
+
+// runSyncer periodically pulls new history of the configured channels into
+// storage until the bot context is canceled.
+func (b *Bot) runSyncer() {
+	interval := b.config.Bot.SyncInterval
+	if interval <= 0 {
+		log.Println("Background sync is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, channel := range b.config.Channels {
+			if b.ctx.Err() != nil {
+				return
+			}
+			if err := b.syncChannel(channel.ID); err != nil {
+				log.Printf("Failed to sync channel %s: %v", channel.ID, err)
+			}
+		}
+
+		select {
+		case <-b.ctx.Done():
+			log.Println("Background sync stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// maxSyncAttempts is how many times the sync tries to process a message
+// before giving up on it.
+const maxSyncAttempts = 5
+
+// syncChannel retries the messages earlier syncs failed to process, pulls the
+// messages posted since the channel checkpoint and moves the checkpoint to the
+// newest one. Messages that fail are stored to be retried on their own, so
+// they don't hold the checkpoint back.
+func (b *Bot) syncChannel(channelID string) error {
+	if err := b.retrySyncFailures(channelID); err != nil {
+		log.Printf("Failed to retry failed messages of channel %s: %v", channelID, err)
+	}
+
+	checkpoint, err := b.repo.GetSyncCheckpoint(channelID)
+	if err != nil {
+		return err
+	}
+
+	from := time.Now().Add(-b.config.Bot.SyncLookback)
+	if checkpoint != "" {
+		if from, err = parseTimestamp(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	messages, err := b.slackClient.FetchMessages(b.ctx, channelID, from, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Synced %d messages from channel %s since %s", len(messages), channelID, from)
+
+	newest, failed := b.processMessages(channelID, messages)
+	for messageTS, err := range failed {
+		if err := b.repo.RecordSyncFailure(channelID, messageTS, err.Error()); err != nil {
+			// keep the checkpoint, so the message is pulled again on the next sync
+			return err
+		}
+	}
+	if newest != "" && newest != checkpoint {
+		if err := b.repo.SaveSyncCheckpoint(channelID, newest); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to process %d of %d messages, they are retried on the next sync", len(failed), len(messages))
+	}
+	return nil
+}
+
+// retrySyncFailures processes the messages earlier syncs failed to process
+// again, forgetting the ones that succeed.
+func (b *Bot) retrySyncFailures(channelID string) error {
+	failures, err := b.repo.ListSyncFailures(channelID, maxSyncAttempts)
+	if err != nil || len(failures) == 0 {
+		return err
+	}
+
+	failed := make(map[string]error)
+	messages := make([]slack.Message, 0, len(failures))
+	for _, failure := range failures {
+		message, err := b.historyMessage(channelID, failure.MessageTS)
+		if err != nil {
+			failed[failure.MessageTS] = err
+			continue
+		}
+		messages = append(messages, message)
+	}
+	_, processFailed := b.processMessages(channelID, messages)
+	maps.Copy(failed, processFailed)
+
+	for _, failure := range failures {
+		err, stillFailing := failed[failure.MessageTS]
+		if !stillFailing {
+			if err := b.repo.DeleteSyncFailure(channelID, failure.MessageTS); err != nil {
+				return err
+			}
+			continue
+		}
+		if failure.Attempts+1 >= maxSyncAttempts {
+			log.Printf("Giving up on message %s in %s after %d attempts: %v", failure.MessageTS, channelID, maxSyncAttempts, err)
+		}
+		if err := b.repo.RecordSyncFailure(channelID, failure.MessageTS, err.Error()); err != nil {
+			return err
+		}
+	}
+	log.Printf("Retried %d failed messages of channel %s, %d still failing", len(failures), channelID, len(failed))
+	return nil
+}
+
+// historyMessage fetches a single top-level message of the channel.
+func (b *Bot) historyMessage(channelID, messageTS string) (slack.Message, error) {
+	messages, err := b.slackClient.FetchReplies(b.ctx, channelID, messageTS)
+	if err != nil {
+		return slack.Message{}, err
+	}
+	for _, message := range messages {
+		if message.Timestamp == messageTS {
+			return message, nil
+		}
+	}
+	return slack.Message{}, fmt.Errorf("message %s not found", messageTS)
+}
+
+// processMessages records the messages as requests and rebuilds the stats of
+// the days they were posted on. Messages are processed by a pool of workers.
+// It returns the timestamp of the newest message and the errors of the
+// messages that couldn't be processed, by their timestamps.
+func (b *Bot) processMessages(channelID string, messages []slack.Message) (string, map[string]error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = make(map[string]error)
+		days   = make(map[time.Time][]string) // day -> messages recorded on it
+		jobs   = make(chan slack.Message)
+	)
+
+	for i := 0; i < b.workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for message := range jobs {
+				date, processed, err := b.processMessage(channelID, message)
+				mu.Lock()
+				if err != nil {
+					log.Printf("Failed to process message %s: %v", message.Timestamp, err)
+					failed[message.Timestamp] = err
+				} else if processed {
+					days[date] = append(days[date], message.Timestamp)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for _, message := range messages {
+		select {
+		case <-b.ctx.Done():
+			break dispatch
+		case jobs <- message:
+			dispatched++
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	// messages left behind on shutdown are picked up by the next sync
+	for _, message := range messages[dispatched:] {
+		failed[message.Timestamp] = fmt.Errorf("not processed before shutdown: %w", b.ctx.Err())
+	}
+
+	for day, recorded := range days {
+		if err := b.rebuildDailyStats(channelID, day); err != nil {
+			log.Printf("Failed to save stats for %s: %v", day.Format("2006-01-02"), err)
+			// processing the messages again rebuilds the day
+			for _, messageTS := range recorded {
+				failed[messageTS] = fmt.Errorf("failed to save stats: %w", err)
+			}
+		}
+	}
+	return newestMessage(messages), failed
+}
+
+// processMessage categorizes a single message and records it as a request.
+// It returns the day the message was posted on and whether it was recorded.
+func (b *Bot) processMessage(channelID string, message slack.Message) (time.Time, bool, error) {
+	date, err := messageDate(message.Timestamp)
+	if err != nil {
+		// retrying won't fix a malformed message, so it is skipped
+		log.Printf("Invalid timestamp for message: %s, error: %v", message.Timestamp, err)
+		return time.Time{}, false, nil
+	}
+
+	var replies []slack.Message
+	if message.ReplyCount > 0 && b.countsThreadReplies(channelID) {
+		replies, err = b.threadReplies(channelID, message)
+		if err != nil {
+			return date, false, fmt.Errorf("failed to fetch replies: %w", err)
+		}
+	}
+
+	reactions, err := b.messageReactions(channelID, message)
+	if err != nil {
+		return date, false, fmt.Errorf("failed to fetch reactions: %w", err)
+	}
+	replyReactions, err := b.replyReactions(channelID, replies)
+	if err != nil {
+		return date, false, fmt.Errorf("failed to fetch reactions of replies: %w", err)
+	}
+	reactions = append(reactions, replyReactions...)
+
+	// reactions on the replies may carry the beacon as well
+	thread := message
+	thread.Reactions = reactions
+	beaconReaction := utils.GetControllingReaction(b.config, channelID)
+	passes := b.statsProcessor.ShouldProcessMessage(channelID, thread, beaconReaction)
+
+	// the permalink is only needed for new requests, which have to pass
+	var permalink string
+	if passes {
+		if _, err := b.repo.GetRequest(channelID, message.Timestamp); errors.Is(err, storage.ErrRequestNotFound) {
+			permalink = b.permalink(channelID, message.Timestamp)
+		}
+	}
+
+	b.requestsMu.Lock()
+	request, assigned, err := b.recordMessage(channelID, message, reactions, passes, permalink)
+	b.requestsMu.Unlock()
+	if err != nil {
+		return date, false, fmt.Errorf("failed to save request: %w", err)
+	}
+	if assigned {
+		b.announceAssignee(channelID, request)
+	}
+	return date, request != nil, nil
+}
+
+// newestMessage returns the timestamp of the newest message.
+func newestMessage(messages []slack.Message) string {
+	var newest time.Time
+	newestTS := ""
+	for _, message := range messages {
+		postedAt, err := parseTimestamp(message.Timestamp)
+		if err != nil {
+			continue
+		}
+		if postedAt.After(newest) {
+			newest, newestTS = postedAt, message.Timestamp
+		}
+	}
+	return newestTS
+}
+
+// workers returns the size of the message processing pool.
+func (b *Bot) workers() int {
+	if b.config.Bot.Workers < 1 {
+		return 1
+	}
+	return b.config.Bot.Workers
+}
+
+// rebuildDailyStats recomputes the stats of the day from the stored requests.
+func (b *Bot) rebuildDailyStats(channelID string, day time.Time) error {
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+
+	// days are counted in local time, see messageDate
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	requests, err := b.repo.ListRequests(storage.RequestQuery{
+		Channel: channelID,
+		Start:   start,
+		End:     start.AddDate(0, 0, 1).Add(-time.Nanosecond),
+	})
+	if err != nil {
+		return err
+	}
+
+	stats := make(map[string]storage.CategoryStats)
+	for i := range requests {
+		for category, counts := range b.statsProcessor.RequestStats(channelID, &requests[i]) {
+			total := stats[category]
+			total.Requests += counts.Requests
+			total.Reactions += counts.Reactions
+			stats[category] = total
+		}
+	}
+	return b.repo.ReplaceStats(channelID, day, stats)
+}
+
+// messageReactions returns the reactions of a message from the history payload,
+// falling back to reactions.get only when the embedded data is truncated.
+func (b *Bot) messageReactions(channelID string, message slack.Message) ([]slack.ItemReaction, error) {
+	if b.statsProcessor.ReactionsComplete(message) {
+		return message.Reactions, nil
+	}
+	log.Printf("Reactions of message %s are truncated, fetching them", message.Timestamp)
+	return b.slackClient.FetchReactions(b.ctx, channelID, message.Timestamp)
+}
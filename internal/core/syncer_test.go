@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncChannel(t *testing.T) {
+	const (
+		firstTS  = "1738144800.000100"
+		secondTS = "1738144900.000100"
+	)
+	config := newTestConfig()
+	config.Bot.SyncLookback = time.Hour
+	bot, client := newTestBot(t, config)
+
+	first := testMessage(firstTS, "", "U9", testReaction("bug", "U1"), testReaction("eyes", "U2"))
+	// the reactions of the second message are truncated and fetching them fails
+	truncated := testReaction("bug", "U1")
+	truncated.Count = 30
+	second := testMessage(secondTS, "", "U8", truncated, testReaction("eyes", "U2"))
+	client.EXPECT().GetPermalinkContext(gomock.Any(), gomock.Any()).Return("https://example.slack.com/p1", nil).AnyTimes()
+
+	// the first sync pulls the lookback
+	client.EXPECT().FetchMessages(gomock.Any(), "C123", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, _ string, from, to time.Time) ([]slack.Message, error) {
+			assert.WithinDuration(t, time.Now().Add(-time.Hour), from, time.Minute)
+			return []slack.Message{second, first}, nil
+		})
+	client.EXPECT().FetchReactions(gomock.Any(), "C123", secondTS).Return(nil, errors.New("ratelimited"))
+
+	err := bot.syncChannel("C123")
+	assert.ErrorContains(t, err, "failed to process 1 of 2 messages")
+	_, err = bot.repo.GetRequest("C123", firstTS)
+	assert.NoError(t, err, "Messages that succeed should be stored")
+	checkpoint, err := bot.repo.GetSyncCheckpoint("C123")
+	assert.NoError(t, err)
+	assert.Equal(t, secondTS, checkpoint, "A failed message should not hold the checkpoint back")
+	failures, err := bot.repo.ListSyncFailures("C123", maxSyncAttempts)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, secondTS, failures[0].MessageTS)
+
+	// the next sync retries the failed message and pulls from the checkpoint
+	complete := testMessage(secondTS, "", "U8", testReaction("bug", "U1"), testReaction("eyes", "U2"))
+	client.EXPECT().FetchReplies(gomock.Any(), "C123", secondTS).Return([]slack.Message{complete}, nil)
+	client.EXPECT().FetchMessages(gomock.Any(), "C123", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, _ string, from, to time.Time) ([]slack.Message, error) {
+			checkpointAt, _ := parseTimestamp(secondTS)
+			assert.Equal(t, checkpointAt, from)
+			return nil, nil
+		})
+
+	assert.NoError(t, bot.syncChannel("C123"))
+	request, err := bot.repo.GetRequest("C123", secondTS)
+	assert.NoError(t, err, "Retried messages should be stored")
+	assert.Equal(t, map[string]int{"bug": 1, "eyes": 1}, request.Reactions)
+	failures, err = bot.repo.ListSyncFailures("C123", maxSyncAttempts)
+	assert.NoError(t, err)
+	assert.Empty(t, failures, "Messages that succeed on retry should be forgotten")
+	checkpoint, err = bot.repo.GetSyncCheckpoint("C123")
+	assert.NoError(t, err)
+	assert.Equal(t, secondTS, checkpoint)
+}
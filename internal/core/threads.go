@@ -0,0 +1,123 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+)
+
+// countsThreadReplies reports whether categorized reactions on thread replies
+// count toward the parent request in the channel.
+func (b *Bot) countsThreadReplies(channelID string) bool {
+	channel, ok := utils.GetChannelConfig(b.config, channelID)
+	return ok && channel.CountThreadReplies
+}
+
+// countsOnReply reports whether the reaction counts toward the parent request
+// when it's on a thread reply, which only categorized reactions do. Beacon,
+// ack and resolution reactions on replies are about the reply.
+func (b *Bot) countsOnReply(channelID, reaction string) bool {
+	_, ok := utils.GetCategoryForReaction(b.config, channelID, reaction)
+	return ok
+}
+
+// threadReplies returns the replies of the thread started by the message, without the parent.
+func (b *Bot) threadReplies(channelID string, message slack.Message) ([]slack.Message, error) {
+	messages, err := b.slackClient.FetchReplies(b.ctx, channelID, message.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]slack.Message, 0, len(messages))
+	for _, reply := range messages {
+		if reply.Timestamp != message.Timestamp {
+			replies = append(replies, reply)
+		}
+	}
+	return replies, nil
+}
+
+// threadParent returns the timestamp of the top-level message the given
+// message belongs to, which is the message itself unless it's a thread reply.
+func (b *Bot) threadParent(channelID, messageTS string) (string, error) {
+	// known requests are always top-level messages
+	_, err := b.repo.GetRequest(channelID, messageTS)
+	if err == nil {
+		return messageTS, nil
+	}
+	if !errors.Is(err, storage.ErrRequestNotFound) {
+		return "", err
+	}
+
+	messages, err := b.slackClient.FetchReplies(b.ctx, channelID, messageTS)
+	if err != nil {
+		return "", err
+	}
+	for _, message := range messages {
+		if message.Timestamp == messageTS && message.ThreadTimestamp != "" {
+			return message.ThreadTimestamp, nil
+		}
+	}
+	return messageTS, nil
+}
+
+// replyReactions returns the reactions of all given replies that count
+// toward the parent request, see countsOnReply. Their users are reactor IDs,
+// so the same user reacting on several messages of the thread is told apart.
+func (b *Bot) replyReactions(channelID string, replies []slack.Message) ([]slack.ItemReaction, error) {
+	var reactions []slack.ItemReaction
+	for _, reply := range replies {
+		replyReactions, err := b.messageReactions(channelID, reply)
+		if err != nil {
+			return nil, err
+		}
+		for _, reaction := range replyReactions {
+			if !b.countsOnReply(channelID, reaction.Name) {
+				continue
+			}
+			users := make([]string, 0, len(reaction.Users))
+			for _, user := range reaction.Users {
+				users = append(users, reactorID(user, reply.Timestamp))
+			}
+			reaction.Users = users
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions, nil
+}
+
+// currentMessage fetches the message as it is right now along with its
+// reactions, including the ones on its thread replies if they count toward
+// the request.
+func (b *Bot) currentMessage(channelID, messageTS string) (slack.Message, []slack.ItemReaction, error) {
+	// the thread includes the parent message
+	messages, err := b.slackClient.FetchReplies(b.ctx, channelID, messageTS)
+	if err != nil {
+		return slack.Message{}, nil, err
+	}
+
+	var parent slack.Message
+	replies := make([]slack.Message, 0, len(messages))
+	for _, message := range messages {
+		if message.Timestamp == messageTS {
+			parent = message
+		} else {
+			replies = append(replies, message)
+		}
+	}
+
+	reactions, err := b.messageReactions(channelID, parent)
+	if err != nil {
+		return parent, nil, err
+	}
+	if !b.countsThreadReplies(channelID) {
+		return parent, reactions, nil
+	}
+	replyReactions, err := b.replyReactions(channelID, replies)
+	if err != nil {
+		return parent, nil, err
+	}
+	return parent, append(reactions, replyReactions...), nil
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyReaction_Replies(t *testing.T) {
+	const parentTS, replyTS = "1738144800.000100", "1738144900.000200"
+	parent := testMessage(parentTS, parentTS, "U1", testReaction("eyes", "U2"))
+	reply := testMessage(replyTS, parentTS, "U2",
+		testReaction("bug", "U3"), testReaction("+1", "U4"), testReaction("white_check_mark", "U3"))
+
+	tests := []struct {
+		name          string
+		countReplies  bool
+		reaction      string
+		repliesErr    error
+		expectedErr   bool
+		expectedStats map[string]int // reactions of the parent request, nil if it isn't stored
+	}{
+		{
+			name:     "replies don't count in the channel",
+			reaction: "bug",
+		},
+		{
+			name:         "categorized reactions count toward the parent",
+			countReplies: true,
+			reaction:     "bug",
+			// the +1 and the resolution on the reply are left out
+			expectedStats: map[string]int{"eyes": 1, "bug": 1},
+		},
+		{
+			name:         "resolution reactions on replies don't count",
+			countReplies: true,
+			reaction:     "white_check_mark",
+		},
+		{
+			name:        "failed thread lookups store nothing",
+			reaction:    "bug",
+			repliesErr:  errors.New("ratelimited"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newTestConfig()
+			config.Channels[0].CountThreadReplies = tt.countReplies
+			bot, client := newTestBot(t, config)
+
+			client.EXPECT().FetchReplies(gomock.Any(), "C123", replyTS).Return([]slack.Message{reply}, tt.repliesErr)
+			if tt.expectedStats != nil {
+				client.EXPECT().FetchReplies(gomock.Any(), "C123", parentTS).Return([]slack.Message{parent, reply}, nil)
+				client.EXPECT().GetPermalinkContext(gomock.Any(), gomock.Any()).Return("https://example.slack.com/p1", nil)
+			}
+
+			err := bot.applyReaction(testReactionChange(replyTS, tt.reaction, "U3", true))
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = bot.repo.GetRequest("C123", replyTS)
+			assert.ErrorIs(t, err, storage.ErrRequestNotFound, "Replies should never be stored as requests")
+			request, err := bot.repo.GetRequest("C123", parentTS)
+			if tt.expectedStats == nil {
+				assert.ErrorIs(t, err, storage.ErrRequestNotFound)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStats, request.Reactions)
+			assert.Nil(t, request.ResolvedAt)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/tracker"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/slack-go/slack"
+)
+
+// Callback IDs of the message shortcut that creates a ticket and its modal.
+const (
+	createTicketShortcutID = "create_ticket"
+	createTicketModalID    = "create_ticket_modal"
+)
+
+// maxSummaryLength keeps summaries within the Jira limit.
+const maxSummaryLength = 255
+
+// ticketTarget is the message a ticket is created from, kept in the modal metadata.
+type ticketTarget struct {
+	Channel   string `json:"channel"`
+	MessageTS string `json:"message_ts"`
+}
+
+// newIssueTracker creates the configured issue tracker, nil if none is configured.
+func newIssueTracker(config utils.IssueTrackerConfig) (tracker.IssueTracker, error) {
+	switch config.Type {
+	case "":
+		return nil, nil
+	case utils.IssueTrackerJira:
+		return tracker.NewJira(config.URL, config.Username, config.Token, config.IssueType, nil), nil
+	case utils.IssueTrackerWebhook:
+		return tracker.NewWebhook(config.URL, config.Headers, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown issue tracker type %q", config.Type)
+	}
+}
+
+// openCreateTicketModal opens the modal to create a ticket from the message
+// the shortcut was used on, pre-filled from the message and its request.
+// Replies create the ticket for their thread.
+func (b *Bot) openCreateTicketModal(callback slack.InteractionCallback) error {
+	channelID := callback.Channel.ID
+	if b.issueTracker == nil {
+		return b.postEphemeralError(channelID, callback.User.ID, "no issue tracker is configured")
+	}
+	if !b.channelConfigExists(channelID) {
+		return b.postEphemeralError(channelID, callback.User.ID, "this channel is not configured for stats")
+	}
+
+	target := ticketTarget{Channel: channelID, MessageTS: callback.Message.Timestamp}
+	text := callback.Message.Text
+	if callback.Message.ThreadTimestamp != "" && callback.Message.ThreadTimestamp != callback.Message.Timestamp {
+		target.MessageTS = callback.Message.ThreadTimestamp
+		text = ""
+	}
+	request, err := b.repo.GetRequest(target.Channel, target.MessageTS)
+	if err != nil {
+		// the modal works without the request, it's only for pre-filling
+		if !errors.Is(err, storage.ErrRequestNotFound) {
+			log.Printf("Failed to get request %s: %v", target.MessageTS, err)
+		}
+		request = nil
+	} else {
+		if text == "" {
+			text = request.Text
+		}
+		if request.TicketKey != "" {
+			return b.postEphemeralError(channelID, callback.User.ID,
+				fmt.Sprintf("this request already has the ticket <%s|%s>", request.TicketURL, request.TicketKey))
+		}
+	}
+	metadata, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("failed to encode ticket modal metadata: %w", err)
+	}
+
+	project := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject(slack.PlainTextType, "e.g. OPS", false, false), "project_input")
+	project.InitialValue = b.issueProject(channelID)
+	summary := slack.NewPlainTextInputBlockElement(
+		slack.NewTextBlockObject(slack.PlainTextType, "What needs to be done?", false, false), "summary_input")
+	summary.InitialValue = ticketSummary(text)
+	summary.MaxLength = maxSummaryLength
+
+	blocks := []slack.Block{
+		slack.NewInputBlock("project", slack.NewTextBlockObject(slack.PlainTextType, "Project 📁", false, false), nil, project),
+		slack.NewInputBlock("summary", slack.NewTextBlockObject(slack.PlainTextType, "Summary ✏️", false, false), nil, summary),
+	}
+	if categories := utils.GetChannelCategories(b.config, channelID); len(categories) > 0 {
+		options := make([]*slack.OptionBlockObject, 0, len(categories))
+		var initial *slack.OptionBlockObject
+		for _, category := range categories[:min(len(categories), maxCategoryOptions)] {
+			option := slack.NewOptionBlockObject(category, slack.NewTextBlockObject(slack.PlainTextType, category, false, false), nil)
+			options = append(options, option)
+			if request != nil && len(request.Categories) > 0 && request.Categories[0].Category == category {
+				initial = option
+			}
+		}
+		picker := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic,
+			slack.NewTextBlockObject(slack.PlainTextType, "Select a category", false, false), "category_picker", options...)
+		picker.InitialOption = initial
+		categoryBlock := slack.NewInputBlock("category", slack.NewTextBlockObject(slack.PlainTextType, "Category 🗂️", false, false), nil, picker)
+		categoryBlock.Optional = true
+		blocks = append(blocks, categoryBlock)
+	}
+
+	modal := slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      createTicketModalID,
+		PrivateMetadata: string(metadata),
+		Title: &slack.TextBlockObject{
+			Type: slack.PlainTextType,
+			Text: "Create ticket🎫",
+		},
+		Blocks: slack.Blocks{BlockSet: blocks},
+		Submit: &slack.TextBlockObject{
+			Type: slack.PlainTextType,
+			Text: "Create",
+		},
+	}
+
+	_, err = b.slackClient.OpenViewContext(b.ctx, callback.TriggerID, modal)
+	if err != nil {
+		log.Printf("Error opening modal: %v", err)
+	}
+	return err
+}
+
+// handleCreateTicketSubmission creates the ticket, links it in the request
+// thread and stores it on the request. Failures are sent to the user.
+func (b *Bot) handleCreateTicketSubmission(callback slack.InteractionCallback) error {
+	var target ticketTarget
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &target); err != nil {
+		return fmt.Errorf("invalid ticket modal metadata: %w", err)
+	}
+	values := callback.View.State.Values
+	issue := tracker.Issue{
+		Project:  strings.TrimSpace(values["project"]["project_input"].Value),
+		Summary:  strings.TrimSpace(values["summary"]["summary_input"].Value),
+		Category: values["category"]["category_picker"].SelectedOption.Value,
+	}
+
+	err := b.createTicket(target, issue, callback.User.ID)
+	if err != nil {
+		if errPost := b.postDM(callback.User.ID, fmt.Sprintf(":x: Failed to create the ticket: %v", err)); errPost != nil {
+			log.Printf("Failed to send DM: %v", errPost)
+		}
+	}
+	return err
+}
+
+// createTicket creates the ticket for the request of the target message.
+// Requests that already have a ticket, or are getting one, are refused.
+func (b *Bot) createTicket(target ticketTarget, issue tracker.Issue, userID string) error {
+	if b.issueTracker == nil {
+		return fmt.Errorf("no issue tracker is configured")
+	}
+
+	snapshot, err := b.prefetchMessage(target.Channel, target.MessageTS)
+	if err != nil {
+		return err
+	}
+	b.requestsMu.Lock()
+	request, _, err := b.loadRequest(target.Channel, target.MessageTS, "", snapshot)
+	if err == nil {
+		err = b.reserveTicket(target, request)
+	}
+	b.requestsMu.Unlock()
+	if err != nil {
+		return err
+	}
+	defer b.releaseTicket(target)
+	issue.Description = fmt.Sprintf("%s\n\nReported in Slack by %s: %s\nTicket created by %s",
+		request.Text, slackUserName(request.Author), request.Permalink, slackUserName(userID))
+
+	ticket, err := b.issueTracker.CreateIssue(b.ctx, issue)
+	if err != nil {
+		return err
+	}
+	log.Printf("Created ticket %s for request %s in %s", ticket.Key, target.MessageTS, target.Channel)
+
+	text := fmt.Sprintf("🎫 <@%s> created <%s|%s> for this request.", userID, ticket.URL, ticket.Key)
+	_, _, err = b.slackClient.PostMessageContext(b.ctx, target.Channel, slack.MsgOptionText(text, false), slack.MsgOptionTS(target.MessageTS))
+	if err != nil {
+		// the ticket exists anyway, so it's still stored
+		log.Printf("Failed to post ticket link for request %s: %v", target.MessageTS, err)
+	}
+	return b.attachTicket(target, ticket)
+}
+
+// reserveTicket marks the request as getting a ticket, unless it already has
+// one or is getting one. The caller must hold requestsMu.
+func (b *Bot) reserveTicket(target ticketTarget, request *storage.Request) error {
+	if request.TicketKey != "" {
+		return fmt.Errorf("the request already has the ticket <%s|%s>", request.TicketURL, request.TicketKey)
+	}
+	key := target.Channel + "/" + target.MessageTS
+	if b.pendingTickets[key] {
+		return fmt.Errorf("a ticket is already being created for the request")
+	}
+	if b.pendingTickets == nil {
+		b.pendingTickets = make(map[string]bool)
+	}
+	b.pendingTickets[key] = true
+	return nil
+}
+
+// releaseTicket lets the request get a ticket again, once it has one or
+// creating it failed.
+func (b *Bot) releaseTicket(target ticketTarget) {
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+	delete(b.pendingTickets, target.Channel+"/"+target.MessageTS)
+}
+
+// attachTicket stores the ticket on the request of the target message. The
+// ticket a request already has is never replaced.
+func (b *Bot) attachTicket(target ticketTarget, ticket *tracker.Ticket) error {
+	date, err := messageDate(target.MessageTS)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := b.prefetchMessage(target.Channel, target.MessageTS)
+	if err != nil {
+		return err
+	}
+
+	b.requestsMu.Lock()
+	defer b.requestsMu.Unlock()
+
+	before := make(map[string]storage.CategoryStats)
+	request, stored, err := b.loadRequest(target.Channel, target.MessageTS, "", snapshot)
+	if err != nil {
+		return err
+	}
+	if stored {
+		before = b.statsProcessor.RequestStats(target.Channel, request)
+	}
+	if request.TicketKey != "" && request.TicketKey != ticket.Key {
+		return fmt.Errorf("the request already has the ticket <%s|%s>, <%s|%s> is not linked to it",
+			request.TicketURL, request.TicketKey, ticket.URL, ticket.Key)
+	}
+	state := b.requestState(target.Channel, request, stored)
+	request.TicketKey = ticket.Key
+	request.TicketURL = ticket.URL
+	b.statsProcessor.Categorize(target.Channel, request)
+	if err := b.repo.SaveRequest(request); err != nil {
+		return err
+	}
+	b.emitRequestChanges(target.Channel, request, state)
+	return b.applyStatsDiff(target.Channel, date, before, b.statsProcessor.RequestStats(target.Channel, request))
+}
+
+// issueProject returns the tracker project of tickets from the channel.
+func (b *Bot) issueProject(channelID string) string {
+	if channel, exists := utils.GetChannelConfig(b.config, channelID); exists && channel.IssueProject != "" {
+		return channel.IssueProject
+	}
+	return b.config.IssueTracker.Project
+}
+
+// ticketSummary returns the first line of the message text, shortened to fit a summary.
+func ticketSummary(text string) string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if runes := []rune(summary); len(runes) > maxSummaryLength {
+		summary = string(runes[:maxSummaryLength-1]) + "…"
+	}
+	return summary
+}
+
+// slackUserName formats a user ID for text outside of Slack.
+func slackUserName(userID string) string {
+	if userID == "" {
+		return "unknown user"
+	}
+	return "Slack user " + userID
+}
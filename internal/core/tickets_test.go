@@ -0,0 +1,63 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/artemlive/tars/pkg/tracker"
+	"github.com/golang/mock/gomock"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+// testTracker creates tickets once it is released.
+type testTracker struct {
+	created atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (t *testTracker) CreateIssue(ctx context.Context, issue tracker.Issue) (*tracker.Ticket, error) {
+	t.started <- struct{}{}
+	<-t.release
+	t.created.Add(1)
+	return &tracker.Ticket{Key: "OPS-1", URL: "https://jira.example.com/browse/OPS-1"}, nil
+}
+
+func TestCreateTicket_Once(t *testing.T) {
+	const messageTS = "1738144800.000100"
+	bot, client := newTestBot(t, newTestConfig())
+	issues := &testTracker{started: make(chan struct{}, 1), release: make(chan struct{})}
+	bot.issueTracker = issues
+
+	reactions := []slack.ItemReaction{testReaction("bug", "U1"), testReaction("eyes", "U2")}
+	_, _, err := bot.recordMessage("C123", testMessage(messageTS, "", "U9"), reactions, true, "")
+	assert.NoError(t, err)
+	client.EXPECT().PostMessageContext(gomock.Any(), "C123", gomock.Any()).Return("", "", nil)
+
+	target := ticketTarget{Channel: "C123", MessageTS: messageTS}
+	created := make(chan error)
+	go func() {
+		created <- bot.createTicket(target, tracker.Issue{Project: "OPS", Summary: "broken"}, "U1")
+	}()
+	<-issues.started
+	err = bot.createTicket(target, tracker.Issue{Project: "OPS", Summary: "broken"}, "U2")
+	assert.ErrorContains(t, err, "already being created", "A request should not get a ticket twice at the same time")
+	close(issues.release)
+	assert.NoError(t, <-created)
+
+	request, err := bot.repo.GetRequest("C123", messageTS)
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS-1", request.TicketKey)
+
+	err = bot.createTicket(target, tracker.Issue{Project: "OPS", Summary: "broken"}, "U2")
+	assert.ErrorContains(t, err, "already has the ticket", "A request with a ticket should be refused")
+	assert.Equal(t, int32(1), issues.created.Load())
+
+	err = bot.attachTicket(target, &tracker.Ticket{Key: "OPS-2", URL: "https://jira.example.com/browse/OPS-2"})
+	assert.ErrorContains(t, err, "OPS-2> is not linked")
+	request, err = bot.repo.GetRequest("C123", messageTS)
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS-1", request.TicketKey, "The ticket of a request should not be replaced")
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/artemlive/tars/pkg/storage"
+	"github.com/artemlive/tars/pkg/utils"
+	"github.com/artemlive/tars/pkg/webhook"
+)
+
+// Events posted to the outgoing webhooks.
+const (
+	eventRequestCategorized = "request.categorized" // the categories a request counts in changed
+	eventRequestAssigned    = "request.assigned"    // a request was assigned to whoever is on call
+	eventRequestAcked       = "request.acked"       // a request got its first ack reaction
+	eventRequestResolved    = "request.resolved"    // a request got its resolution reaction
+	eventRequestReopened    = "request.reopened"    // the resolution reaction of a request was taken back
+	eventSLABreached        = "sla.breached"        // a request missed an SLA target
+)
+
+var webhookEvents = []string{
+	eventRequestCategorized,
+	eventRequestAssigned,
+	eventRequestAcked,
+	eventRequestResolved,
+	eventRequestReopened,
+	eventSLABreached,
+}
+
+// newWebhookDispatcher creates the dispatcher of the configured webhooks, nil
+// if there are none. Deliveries that fail for good are stored in the database.
+func (b *Bot) newWebhookDispatcher(configs []utils.WebhookConfig) (*webhook.Dispatcher, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	endpoints := make([]webhook.Endpoint, 0, len(configs))
+	for _, config := range configs {
+		if config.URL == "" {
+			return nil, fmt.Errorf("webhook without url")
+		}
+		for _, event := range config.Events {
+			if !slices.Contains(webhookEvents, event) {
+				return nil, fmt.Errorf("webhook %s: unknown event %q", config.URL, event)
+			}
+		}
+		endpoints = append(endpoints, webhook.Endpoint{URL: config.URL, Events: config.Events, Secret: config.Secret})
+	}
+	return webhook.NewDispatcher(endpoints, b.recordWebhookFailure), nil
+}
+
+// recordWebhookFailure stores a delivery that failed after all attempts, so
+// it can be looked into and redelivered by hand.
+func (b *Bot) recordWebhookFailure(failure webhook.Failure) {
+	err := b.repo.RecordWebhookFailure(&storage.WebhookFailure{
+		URL:        failure.Endpoint.URL,
+		Event:      failure.Event,
+		DeliveryID: failure.DeliveryID,
+		Payload:    string(failure.Payload),
+		Attempts:   failure.Attempts,
+		Error:      failure.Err.Error(),
+	})
+	if err != nil {
+		log.Printf("Failed to record webhook failure %s: %v", failure.DeliveryID, err)
+	}
+}
+
+// emit posts the event to the webhooks in the background.
+func (b *Bot) emit(event string, data any) {
+	if b.webhooks == nil {
+		return
+	}
+	b.webhooks.Dispatch(b.ctx, event, data)
+}
+
+// requestPayload is a request as posted to the webhooks.
+type requestPayload struct {
+	Channel        string     `json:"channel"`
+	MessageTS      string     `json:"message_ts"`
+	Permalink      string     `json:"permalink,omitempty"`
+	Author         string     `json:"author,omitempty"`
+	Assignee       string     `json:"assignee,omitempty"`
+	Categories     []string   `json:"categories"`
+	CategorySource string     `json:"category_source,omitempty"`
+	CategorizedBy  string     `json:"categorized_by,omitempty"` // user who picked the category by hand
+	Counted        bool       `json:"counted"`                  // whether the request passes the beacon mode
+	PostedAt       time.Time  `json:"posted_at"`
+	AckedAt        *time.Time `json:"acked_at,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	TicketKey      string     `json:"ticket_key,omitempty"`
+}
+
+func (b *Bot) requestPayload(channelID string, request *storage.Request) requestPayload {
+	payload := requestPayload{
+		Channel:       channelID,
+		MessageTS:     request.MessageTS,
+		Permalink:     request.Permalink,
+		Author:        request.Author,
+		Assignee:      request.Assignee,
+		Categories:    request.CategoryNames(),
+		CategorizedBy: request.CategorizedBy,
+		Counted:       b.statsProcessor.ShouldCountRequest(channelID, request),
+		PostedAt:      request.PostedAt,
+		AckedAt:       request.AckedAt,
+		ResolvedAt:    request.ResolvedAt,
+		TicketKey:     request.TicketKey,
+	}
+	if len(request.Categories) > 0 {
+		payload.CategorySource = request.Categories[0].Source
+	}
+	return payload
+}
+
+// slaBreachPayload is an SLA breach as posted to the webhooks.
+type slaBreachPayload struct {
+	Kind     string         `json:"kind"` // "ack" or "resolve"
+	Category string         `json:"category"`
+	Target   string         `json:"target"`
+	DueAt    time.Time      `json:"due_at"`
+	Request  requestPayload `json:"request"`
+}
+
+// requestState is what the webhooks are told about when it changes.
+type requestState struct {
+	counted  map[string]storage.CategoryStats // what the request adds to the stats
+	assignee string
+	acked    bool
+	resolved bool
+}
+
+func (b *Bot) requestState(channelID string, request *storage.Request, stored bool) requestState {
+	if !stored {
+		return requestState{}
+	}
+	return requestState{
+		counted:  b.statsProcessor.RequestStats(channelID, request),
+		assignee: request.Assignee,
+		acked:    request.AckedAt != nil,
+		resolved: request.ResolvedAt != nil,
+	}
+}
+
+// emitRequestChanges posts the events of what changed since the request was
+// in the before state, whether the change came from a live event or from the
+// channel history.
+func (b *Bot) emitRequestChanges(channelID string, request *storage.Request, before requestState) {
+	after := b.requestState(channelID, request, true)
+	var events []string
+	if len(after.counted) > 0 && !sameCategories(before.counted, after.counted) {
+		events = append(events, eventRequestCategorized)
+	}
+	if after.assignee != "" && after.assignee != before.assignee {
+		events = append(events, eventRequestAssigned)
+	}
+	if after.acked && !before.acked {
+		events = append(events, eventRequestAcked)
+	}
+	if after.resolved && !before.resolved {
+		events = append(events, eventRequestResolved)
+	}
+	if !after.resolved && before.resolved {
+		events = append(events, eventRequestReopened)
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	payload := b.requestPayload(channelID, request)
+	for _, event := range events {
+		b.emit(event, payload)
+	}
+}
+
+// sameCategories reports whether the request counts in the same categories.
+func sameCategories(a, b map[string]storage.CategoryStats) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for category := range a {
+		if _, exists := b[category]; !exists {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+// Package calendar implements business hours: weekly opening hours in a
+// timezone, minus holidays.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxDays bounds how far Add searches for open hours.
+const maxDays = 3660
+
+// Span is an open range of a day, in minutes since midnight.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Calendar tells business time apart from the rest.
+type Calendar struct {
+	location *time.Location
+	hours    map[time.Weekday][]Span
+	holidays map[string]bool // "2006-01-02" in the calendar location
+}
+
+// New creates a calendar open during the weekly hours in the location,
+// except on the holidays.
+func New(location *time.Location, hours map[time.Weekday][]Span, holidays []time.Time) (*Calendar, error) {
+	open := false
+	for day, spans := range hours {
+		for _, span := range spans {
+			if span.Start < 0 || span.End > 24*60 || span.Start >= span.End {
+				return nil, fmt.Errorf("invalid hours on %s: %d-%d", day, span.Start, span.End)
+			}
+			open = true
+		}
+	}
+	if !open {
+		return nil, fmt.Errorf("calendar is never open")
+	}
+
+	calendar := &Calendar{
+		location: location,
+		hours:    hours,
+		holidays: make(map[string]bool),
+	}
+	for _, holiday := range holidays {
+		calendar.holidays[holiday.Format("2006-01-02")] = true
+	}
+	return calendar, nil
+}
+
+// Load creates a calendar from configuration values: a timezone name, hours
+// per lowercase weekday name like "09:00-17:00" or "09:00-12:00,13:00-17:00",
+// and an optional holidays file, see LoadHolidays.
+func Load(timezone string, weekly map[string]string, holidaysFile string) (*Calendar, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	hours, err := ParseWeeklyHours(weekly)
+	if err != nil {
+		return nil, err
+	}
+	var holidays []time.Time
+	if holidaysFile != "" {
+		if holidays, err = LoadHolidays(holidaysFile); err != nil {
+			return nil, err
+		}
+	}
+	return New(location, hours, holidays)
+}
+
+// ParseWeeklyHours parses hours per weekday name, e.g. "monday": "09:00-17:00".
+func ParseWeeklyHours(weekly map[string]string) (map[time.Weekday][]Span, error) {
+	weekdays := make(map[string]time.Weekday)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		weekdays[strings.ToLower(day.String())] = day
+	}
+
+	hours := make(map[time.Weekday][]Span)
+	for name, value := range weekly {
+		day, ok := weekdays[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		for _, part := range strings.Split(value, ",") {
+			span, err := parseSpan(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid hours on %s: %w", name, err)
+			}
+			hours[day] = append(hours[day], span)
+		}
+	}
+	return hours, nil
+}
+
+// parseSpan parses a range like "09:00-17:00", "24:00" ends at midnight.
+func parseSpan(value string) (Span, error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return Span{}, fmt.Errorf("%q is not a range like 09:00-17:00", value)
+	}
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return Span{}, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return Span{}, err
+	}
+	return Span{Start: startMinutes, End: endMinutes}, nil
+}
+
+func parseClock(value string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d:%d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", value, err)
+	}
+	if hours < 0 || minutes < 0 || minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+// LoadHolidays reads holidays from a file with one "2006-01-02" date per
+// line, optionally followed by a name. Empty lines and lines starting with
+// "#" are skipped.
+func LoadHolidays(path string) ([]time.Time, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open holidays file: %w", err)
+	}
+	defer file.Close()
+
+	var holidays []time.Time
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		date, _, _ := strings.Cut(text, " ")
+		holiday, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday on line %d of %s: %w", line, path, err)
+		}
+		holidays = append(holidays, holiday)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+	return holidays, nil
+}
+
+// Location returns the timezone of the calendar.
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// IsOpen reports whether t falls within business hours.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	for _, span := range c.openSpans(t) {
+		if !t.Before(span[0]) && t.Before(span[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Elapsed returns the business time between from and to, zero if to is not after from.
+func (c *Calendar) Elapsed(from, to time.Time) time.Duration {
+	var elapsed time.Duration
+	for day := from; !c.startOfDay(day).After(to); day = c.nextDay(day) {
+		for _, span := range c.openSpans(day) {
+			start, end := later(span[0], from), earlier(span[1], to)
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+	}
+	return elapsed
+}
+
+// Add returns the time when d of business time has passed since from.
+func (c *Calendar) Add(from time.Time, d time.Duration) time.Time {
+	day := from
+	for i := 0; i < maxDays; i, day = i+1, c.nextDay(day) {
+		for _, span := range c.openSpans(day) {
+			start := later(span[0], from)
+			if !span[1].After(start) {
+				continue
+			}
+			if open := span[1].Sub(start); d <= open {
+				return start.Add(d)
+			} else {
+				d -= open
+			}
+		}
+	}
+	// never reached with a calendar that is open every week
+	return from.Add(d)
+}
+
+// openSpans returns the open hours of the day t falls on, in order.
+func (c *Calendar) openSpans(t time.Time) [][2]time.Time {
+	dayStart := c.startOfDay(t)
+	if c.holidays[dayStart.Format("2006-01-02")] {
+		return nil
+	}
+	var spans [][2]time.Time
+	for _, span := range c.hours[dayStart.Weekday()] {
+		spans = append(spans, [2]time.Time{c.atMinute(dayStart, span.Start), c.atMinute(dayStart, span.End)})
+	}
+	sortSpans(spans)
+	return spans
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.location)
+}
+
+func (c *Calendar) nextDay(t time.Time) time.Time {
+	day := c.startOfDay(t)
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.location)
+}
+
+// atMinute returns the wall clock time of the day, which stays right across DST changes.
+func (c *Calendar) atMinute(dayStart time.Time, minute int) time.Time {
+	return time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), minute/60, minute%60, 0, 0, c.location)
+}
+
+func sortSpans(spans [][2]time.Time) {
+	for i := 1; i < len(spans); i++ {
+		for j := i; j > 0 && spans[j][0].Before(spans[j-1][0]); j-- {
+			spans[j], spans[j-1] = spans[j-1], spans[j]
+		}
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCalendar is open 09:00-17:00 on weekdays in Berlin, except on
+// Wednesday, January 1st 2025.
+func newTestCalendar(t *testing.T) *Calendar {
+	weekdays := map[string]string{
+		"monday":    "09:00-17:00",
+		"tuesday":   "09:00-17:00",
+		"wednesday": "09:00-17:00",
+		"thursday":  "09:00-17:00",
+		"Friday":    "09:00-12:00, 13:00-17:00",
+	}
+	holidays, err := os.CreateTemp("", "holidays_*.txt")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.Remove(holidays.Name()) })
+	_, err = holidays.WriteString("# public holidays\n\n2025-01-01 New Year's Day\n")
+	assert.NoError(t, err)
+	assert.NoError(t, holidays.Close())
+
+	calendar, err := Load("Europe/Berlin", weekdays, holidays.Name())
+	assert.NoError(t, err)
+	return calendar
+}
+
+func TestElapsed(t *testing.T) {
+	calendar := newTestCalendar(t)
+	berlin := calendar.Location()
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.January, day, hour, minute, 0, 0, berlin)
+	}
+
+	// Thursday 10:00 to 11:30
+	assert.Equal(t, 90*time.Minute, calendar.Elapsed(at(2, 10, 0), at(2, 11, 30)))
+	// Friday 16:30 to Monday 09:30, over the weekend
+	assert.Equal(t, time.Hour, calendar.Elapsed(at(3, 16, 30), at(6, 9, 30)))
+	// Friday 11:00 to 14:00, over the lunch break
+	assert.Equal(t, 2*time.Hour, calendar.Elapsed(at(3, 11, 0), at(3, 14, 0)))
+	// Tuesday 16:00 to Thursday 10:00, over the holiday
+	assert.Equal(t, 2*time.Hour, calendar.Elapsed(time.Date(2024, time.December, 31, 16, 0, 0, 0, berlin), at(2, 10, 0)))
+	// Saturday to Sunday
+	assert.Equal(t, time.Duration(0), calendar.Elapsed(at(4, 10, 0), at(5, 18, 0)))
+	// Reversed
+	assert.Equal(t, time.Duration(0), calendar.Elapsed(at(2, 11, 0), at(2, 10, 0)))
+	// Other timezones are converted, 09:00 UTC is 10:00 in Berlin
+	assert.Equal(t, time.Hour, calendar.Elapsed(time.Date(2025, time.January, 2, 9, 0, 0, 0, time.UTC), at(2, 11, 0)))
+}
+
+func TestAdd(t *testing.T) {
+	calendar := newTestCalendar(t)
+	berlin := calendar.Location()
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.January, day, hour, minute, 0, 0, berlin)
+	}
+
+	assert.Equal(t, at(2, 10, 30), calendar.Add(at(2, 10, 0), 30*time.Minute))
+	// Friday evening requests are due on Monday
+	assert.Equal(t, at(6, 9, 30), calendar.Add(at(3, 18, 0), 30*time.Minute))
+	// Lunch breaks don't count
+	assert.Equal(t, at(3, 13, 30), calendar.Add(at(3, 11, 30), time.Hour))
+	// The holiday is skipped
+	assert.Equal(t, at(2, 9, 30), calendar.Add(time.Date(2024, time.December, 31, 17, 0, 0, 0, berlin), 30*time.Minute))
+	// A whole working day
+	assert.Equal(t, at(7, 10, 0), calendar.Add(at(6, 10, 0), 8*time.Hour))
+
+	from := at(3, 16, 30)
+	assert.Equal(t, 4*time.Hour, calendar.Elapsed(from, calendar.Add(from, 4*time.Hour)))
+}
+
+func TestIsOpen(t *testing.T) {
+	calendar := newTestCalendar(t)
+	berlin := calendar.Location()
+
+	assert.True(t, calendar.IsOpen(time.Date(2025, time.January, 2, 9, 0, 0, 0, berlin)))
+	assert.False(t, calendar.IsOpen(time.Date(2025, time.January, 2, 17, 0, 0, 0, berlin)), "Closing time should be closed")
+	assert.False(t, calendar.IsOpen(time.Date(2025, time.January, 3, 12, 30, 0, 0, berlin)))
+	assert.False(t, calendar.IsOpen(time.Date(2025, time.January, 1, 10, 0, 0, 0, berlin)), "Holidays should be closed")
+	assert.False(t, calendar.IsOpen(time.Date(2025, time.January, 4, 10, 0, 0, 0, berlin)))
+}
+
+func TestElapsed_DaylightSavingTime(t *testing.T) {
+	calendar, err := Load("Europe/Berlin", map[string]string{"sunday": "00:00-24:00"}, "")
+	assert.NoError(t, err)
+	berlin := calendar.Location()
+
+	// clocks go forward on March 30th 2025, so the day has 23 hours
+	day := time.Date(2025, time.March, 30, 0, 0, 0, 0, berlin)
+	assert.Equal(t, 23*time.Hour, calendar.Elapsed(day, day.AddDate(0, 0, 1)))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := Load("Mars/Olympus_Mons", map[string]string{"monday": "09:00-17:00"}, "")
+	assert.Error(t, err, "Unknown timezones should be rejected")
+
+	_, err = Load("UTC", map[string]string{"someday": "09:00-17:00"}, "")
+	assert.Error(t, err, "Unknown weekdays should be rejected")
+
+	_, err = Load("UTC", map[string]string{"monday": "17:00-09:00"}, "")
+	assert.Error(t, err, "Ranges should end after they start")
+
+	_, err = Load("UTC", map[string]string{"monday": "9am"}, "")
+	assert.Error(t, err)
+
+	_, err = Load("UTC", nil, "")
+	assert.Error(t, err, "Calendars that are never open should be rejected")
+
+	_, err = Load("UTC", map[string]string{"monday": "09:00-17:00"}, "nonexistent.txt")
+	assert.Error(t, err)
+}
+
+func TestLoadHolidays_Invalid(t *testing.T) {
+	file, err := os.CreateTemp("", "holidays_*.txt")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("2025-01-01\nChristmas\n")
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	_, err = LoadHolidays(file.Name())
+	assert.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,163 @@
+package oncall
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slackUserPattern matches a Slack user ID, e.g. "U024BE7LH" or "<@U024BE7LH>".
+var slackUserPattern = regexp.MustCompile(`\b[UW][A-Z0-9]{6,}\b`)
+
+// Shift is a period a user is on call.
+type Shift struct {
+	User  string
+	Start time.Time
+	End   time.Time
+}
+
+// ICSSchedule is a schedule from the events of an iCalendar file.
+type ICSSchedule struct {
+	shifts []Shift
+}
+
+// LoadICS reads a schedule from an iCalendar file, see ParseICS.
+func LoadICS(path string) (*ICSSchedule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar file: %w", err)
+	}
+	defer file.Close()
+
+	schedule, err := ParseICS(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar file %s: %w", path, err)
+	}
+	return schedule, nil
+}
+
+// ParseICS reads the shifts of a schedule from iCalendar events. The summary
+// of every event has to contain the Slack user ID of the person on call.
+// Recurring events aren't expanded, only their first occurrence counts.
+func ParseICS(r io.Reader) (*ICSSchedule, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &ICSSchedule{}
+	var shift *Shift
+	var summary string
+	for _, line := range lines {
+		name, params, value := parseProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			shift, summary = &Shift{}, ""
+		case shift == nil:
+			// outside of events
+		case name == "SUMMARY":
+			summary = value
+		case name == "DTSTART" || name == "DTEND":
+			at, err := parseDateTime(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+			}
+			if name == "DTSTART" {
+				shift.Start = at
+			} else {
+				shift.End = at
+			}
+		case name == "END" && value == "VEVENT":
+			shift.User = slackUserPattern.FindString(summary)
+			if shift.User == "" {
+				return nil, fmt.Errorf("event %q has no Slack user ID in its summary", summary)
+			}
+			if shift.Start.IsZero() || !shift.End.After(shift.Start) {
+				return nil, fmt.Errorf("event %q has no valid start and end", summary)
+			}
+			schedule.shifts = append(schedule.shifts, *shift)
+			shift = nil
+		}
+	}
+	return schedule, nil
+}
+
+// Shifts returns the shifts of the schedule in the order of the file.
+func (s *ICSSchedule) Shifts() []Shift {
+	return s.shifts
+}
+
+// OnCall returns the user of the shift covering the time. When shifts
+// overlap, the one that started last wins, so overrides can be added as
+// shorter events on top of the regular shifts.
+func (s *ICSSchedule) OnCall(at time.Time) (string, bool) {
+	var current *Shift
+	for i, shift := range s.shifts {
+		if at.Before(shift.Start) || !at.Before(shift.End) {
+			continue
+		}
+		if current == nil || !shift.Start.Before(current.Start) {
+			current = &s.shifts[i]
+		}
+	}
+	if current == nil {
+		return "", false
+	}
+	return current.User, true
+}
+
+// unfoldLines reads the content lines, joining the ones folded over several
+// physical lines.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line like "DTSTART;TZID=Europe/Berlin:20250106T090000"
+// into its name, parameters and value.
+func parseProperty(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value)
+}
+
+// parseDateTime parses an iCalendar date or date-time. Times without a
+// timezone are taken as UTC.
+func parseDateTime(params map[string]string, value string) (time.Time, error) {
+	location := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, err
+		}
+	}
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == len("20060102"):
+		return time.ParseInLocation("20060102", value, location)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse("20060102T150405Z", value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, location)
+	}
+}
@@ -0,0 +1,82 @@
+package oncall
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:On call: <@U0AAAAAAA>\r\n" +
+	"DTSTART:20250106T090000Z\r\n" +
+	"DTEND:20250113T090000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Override U0BBBBBBB while U0AAAAAAA is at the \r\n" +
+	" dentist\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20250108T100000\r\n" +
+	"DTEND;TZID=Europe/Berlin:20250108T140000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:U0CCCCCCC\r\n" +
+	"DTSTART;VALUE=DATE:20250113\r\n" +
+	"DTEND;VALUE=DATE:20250120\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	schedule, err := ParseICS(strings.NewReader(testCalendar))
+	assert.NoError(t, err)
+	assert.Len(t, schedule.Shifts(), 3)
+
+	tests := []struct {
+		at   time.Time
+		user string
+		ok   bool
+	}{
+		{time.Date(2025, time.January, 6, 8, 59, 0, 0, time.UTC), "", false},
+		{time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC), "U0AAAAAAA", true},
+		// 09:30 UTC is 10:30 in Berlin, during the override
+		{time.Date(2025, time.January, 8, 9, 30, 0, 0, time.UTC), "U0BBBBBBB", true},
+		{time.Date(2025, time.January, 8, 13, 0, 0, 0, time.UTC), "U0AAAAAAA", true},
+		{time.Date(2025, time.January, 13, 9, 0, 0, 0, time.UTC), "U0CCCCCCC", true},
+		{time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC), "", false},
+	}
+	for _, tt := range tests {
+		user, ok := schedule.OnCall(tt.at)
+		assert.Equal(t, tt.ok, ok, "on call at %s", tt.at)
+		assert.Equal(t, tt.user, user, "on call at %s", tt.at)
+	}
+}
+
+func TestParseICS_Invalid(t *testing.T) {
+	_, err := ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Alice\nDTSTART:20250106T090000Z\nDTEND:20250113T090000Z\nEND:VEVENT\n"))
+	assert.ErrorContains(t, err, "no Slack user ID")
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:U0AAAAAAA\nDTSTART:20250106T090000Z\nEND:VEVENT\n"))
+	assert.Error(t, err, "Events without an end should be rejected")
+
+	_, err = ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:U0AAAAAAA\nDTSTART:tomorrow\nEND:VEVENT\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadICS(t *testing.T) {
+	file, err := os.CreateTemp("", "oncall_*.ics")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(testCalendar)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	schedule, err := LoadICS(file.Name())
+	assert.NoError(t, err)
+	assert.Len(t, schedule.Shifts(), 3)
+
+	_, err = LoadICS("nonexistent.ics")
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+// Package oncall tells who is on call, from a fixed rotation or from the
+// events of an iCalendar file.
+package oncall
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultShiftLength is how long a rotation shift lasts unless configured.
+const DefaultShiftLength = 7 * 24 * time.Hour
+
+// Schedule tells who is on call at a given time.
+type Schedule interface {
+	// OnCall returns the Slack user ID of the person on call at the time.
+	OnCall(at time.Time) (string, bool)
+}
+
+// Rotation hands the shift over to the next user at fixed intervals.
+type Rotation struct {
+	users []string
+	start time.Time
+	shift time.Duration
+}
+
+// NewRotation creates a rotation of the users in the given order, the first
+// one starting the first shift at start.
+func NewRotation(users []string, start time.Time, shift time.Duration) (*Rotation, error) {
+	if len(users) == 0 {
+		return nil, fmt.Errorf("rotation has no users")
+	}
+	if shift <= 0 {
+		return nil, fmt.Errorf("invalid shift length %s", shift)
+	}
+	return &Rotation{users: users, start: start, shift: shift}, nil
+}
+
+// OnCall returns the user whose shift covers the time, the rotation repeats
+// before its start too.
+func (r *Rotation) OnCall(at time.Time) (string, bool) {
+	shifts := int64(at.Sub(r.start) / r.shift)
+	if at.Before(r.start) && at.Sub(r.start)%r.shift != 0 {
+		shifts-- // round down
+	}
+	n := int64(len(r.users))
+	return r.users[((shifts%n)+n)%n], true
+}
@@ -0,0 +1,41 @@
+package oncall
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotation(t *testing.T) {
+	start := time.Date(2025, time.January, 6, 9, 0, 0, 0, time.UTC)
+	rotation, err := NewRotation([]string{"U1", "U2", "U3"}, start, DefaultShiftLength)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		at   time.Time
+		user string
+	}{
+		{start, "U1"},
+		{start.Add(DefaultShiftLength - time.Second), "U1"},
+		{start.Add(DefaultShiftLength), "U2"},
+		{start.AddDate(0, 0, 20), "U3"},
+		{start.AddDate(0, 0, 21), "U1"},
+		{start.Add(-time.Second), "U3"},
+		{start.Add(-DefaultShiftLength), "U3"},
+		{start.Add(-DefaultShiftLength - time.Second), "U2"},
+	}
+	for _, tt := range tests {
+		user, ok := rotation.OnCall(tt.at)
+		assert.True(t, ok)
+		assert.Equal(t, tt.user, user, "on call at %s", tt.at)
+	}
+}
+
+func TestNewRotation_Invalid(t *testing.T) {
+	_, err := NewRotation(nil, time.Now(), time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewRotation([]string{"U1"}, time.Now(), 0)
+	assert.Error(t, err)
+}
@@ -17,12 +17,18 @@ type Client interface {
 	RegisterInteractiveHandler(interactionType slack.InteractionType, callbackID string, handler InteractiveHandler)
 	ListenEvents(ctx context.Context) error
 	FetchMessages(ctx context.Context, channelID string, from, to time.Time) ([]slack.Message, error)
+	FetchReplies(ctx context.Context, channelID, threadTS string) ([]slack.Message, error)
 	OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
 	PostEphemeralContext(ctx context.Context, channel, user string, options ...slack.MsgOption) (string, error)
 	FetchReactions(ctx context.Context, channelID, timestamp string) ([]slack.ItemReaction, error)
 	PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error)
 	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
 	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error)
+	GetEmojiContext(ctx context.Context) (map[string]string, error)
+	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
 }
 
 // Client wraps the Slack API and socket mode client.
@@ -34,6 +40,7 @@ type SlackClient struct {
 	commandsRegistry   *CommandsRegistry
 	interaciveRegistry *InteractiveRegistry
 	context            context.Context
+	retryPolicy        RetryPolicy
 }
 
 // NewSlackClient initializes a new Slack client for socket mode.
@@ -54,6 +61,7 @@ func NewSlackClient(botToken, appToken string) *SlackClient {
 		eventsRegistry:     NewEventsRegistry(),
 		commandsRegistry:   NewCommandsRegistry(),
 		interaciveRegistry: NewInteractiveRegistry(),
+		retryPolicy:        DefaultRetryPolicy,
 	}
 	client.registerConnectionHandlers()
 	return client
@@ -156,15 +164,19 @@ func (s *SlackClient) FetchMessages(ctx context.Context, channelID string, from,
 	var allMessages []slack.Message
 	cursor := ""
 	for {
-		history, err := s.api.GetConversationHistoryContext(
-			ctx,
-			&slack.GetConversationHistoryParameters{
-				ChannelID: channelID,
-				Oldest:    fmt.Sprintf("%f", float64(from.Unix())),
-				Latest:    fmt.Sprintf("%f", float64(to.Unix())),
-				Cursor:    cursor,
-			},
-		)
+		var history *slack.GetConversationHistoryResponse
+		err := s.retryPolicy.Do(ctx, "conversations.history", func() (err error) {
+			history, err = s.api.GetConversationHistoryContext(
+				ctx,
+				&slack.GetConversationHistoryParameters{
+					ChannelID: channelID,
+					Oldest:    fmt.Sprintf("%f", float64(from.Unix())),
+					Latest:    fmt.Sprintf("%f", float64(to.Unix())),
+					Cursor:    cursor,
+				},
+			)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -178,6 +190,39 @@ func (s *SlackClient) FetchMessages(ctx context.Context, channelID string, from,
 	return allMessages, nil
 }
 
+// FetchReplies returns the messages of the thread the given message belongs to.
+// The thread parent is the first message of the result.
+func (s *SlackClient) FetchReplies(ctx context.Context, channelID, threadTS string) ([]slack.Message, error) {
+	log.Printf("Fetching replies of %s in %s", threadTS, channelID)
+	var allMessages []slack.Message
+	cursor := ""
+	for {
+		var messages []slack.Message
+		var hasMore bool
+		var nextCursor string
+		err := s.retryPolicy.Do(ctx, "conversations.replies", func() (err error) {
+			messages, hasMore, nextCursor, err = s.api.GetConversationRepliesContext(
+				ctx,
+				&slack.GetConversationRepliesParameters{
+					ChannelID: channelID,
+					Timestamp: threadTS,
+					Cursor:    cursor,
+				},
+			)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch replies: %w", err)
+		}
+		allMessages = append(allMessages, messages...)
+		if !hasMore || nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+	return allMessages, nil
+}
+
 func (s *SlackClient) OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
 	return s.api.OpenViewContext(ctx, triggerID, view)
 }
@@ -187,10 +232,14 @@ func (s *SlackClient) PostEphemeralContext(ctx context.Context, channel, user st
 }
 
 func (s *SlackClient) FetchReactions(ctx context.Context, channelID, timestamp string) ([]slack.ItemReaction, error) {
-	reactions, err := s.api.GetReactionsContext(ctx, slack.ItemRef{
-		Channel:   channelID,
-		Timestamp: timestamp,
-	}, slack.GetReactionsParameters{})
+	var reactions []slack.ItemReaction
+	err := s.retryPolicy.Do(ctx, "reactions.get", func() (err error) {
+		reactions, err = s.api.GetReactionsContext(ctx, slack.ItemRef{
+			Channel:   channelID,
+			Timestamp: timestamp,
+		}, slack.GetReactionsParameters{Full: true})
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch reactions: %w", err)
 	}
@@ -208,3 +257,47 @@ func (s *SlackClient) UploadFileV2Context(ctx context.Context, params slack.Uplo
 func (s *SlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
 	return s.api.OpenConversationContext(ctx, params)
 }
+
+func (s *SlackClient) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	var permalink string
+	err := s.retryPolicy.Do(ctx, "chat.getPermalink", func() (err error) {
+		permalink, err = s.api.GetPermalinkContext(ctx, params)
+		return err
+	})
+	return permalink, err
+}
+
+// GetEmojiContext returns the custom emoji of the workspace from emoji.list.
+// Aliases have an "alias:<emoji>" value instead of an image URL.
+func (s *SlackClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	var emoji map[string]string
+	err := s.retryPolicy.Do(ctx, "emoji.list", func() (err error) {
+		emoji, err = s.api.GetEmojiContext(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch emoji: %w", err)
+	}
+	return emoji, nil
+}
+
+func (s *SlackClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	return s.api.AddReactionContext(ctx, name, item)
+}
+
+func (s *SlackClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	return s.api.UpdateMessageContext(ctx, channelID, timestamp, options...)
+}
+
+// AuthTestContext returns who the bot token belongs to from auth.test.
+func (s *SlackClient) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	var response *slack.AuthTestResponse
+	err := s.retryPolicy.Do(ctx, "auth.test", func() (err error) {
+		response, err = s.api.AuthTestContext(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to identify the bot: %w", err)
+	}
+	return response, nil
+}
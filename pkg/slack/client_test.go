@@ -56,6 +56,44 @@ func TestFetchMessages_Error(t *testing.T) {
 	assert.Nil(t, messages)
 }
 
+// TestFetchReplies ensures FetchReplies returns the thread messages
+func TestFetchReplies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		FetchReplies(gomock.Any(), "C123456", "1678901234.567890").
+		Return([]slack.Message{
+			{Msg: slack.Msg{Timestamp: "1678901234.567890", ThreadTimestamp: "1678901234.567890", Text: "Help!"}},
+			{Msg: slack.Msg{Timestamp: "1678901299.000100", ThreadTimestamp: "1678901234.567890", Text: "Looking"}},
+		}, nil).
+		Times(1)
+
+	replies, err := mockSlack.FetchReplies(context.Background(), "C123456", "1678901234.567890")
+	assert.NoError(t, err)
+	assert.Len(t, replies, 2)
+	assert.Equal(t, "Looking", replies[1].Text)
+}
+
+// TestFetchReplies_Error simulates an API failure
+func TestFetchReplies_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		FetchReplies(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("Slack API error")).
+		Times(1)
+
+	replies, err := mockSlack.FetchReplies(context.Background(), "C123456", "1678901234.567890")
+	assert.Error(t, err)
+	assert.Nil(t, replies)
+}
+
 // TestPostMessageContext ensures PostMessageContext sends messages correctly
 func TestPostMessageContext(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -320,3 +358,87 @@ func TestPostEphemeralContext_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, ts)
 }
+
+func TestGetPermalinkContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		GetPermalinkContext(gomock.Any(), &slack.PermalinkParameters{Channel: "C123456", Ts: "1678901234.567890"}).
+		Return("https://example.slack.com/archives/C123456/p1678901234567890", nil).
+		Times(1)
+
+	permalink, err := mockSlack.GetPermalinkContext(context.Background(), &slack.PermalinkParameters{Channel: "C123456", Ts: "1678901234.567890"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.slack.com/archives/C123456/p1678901234567890", permalink)
+}
+
+func TestGetEmojiContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		GetEmojiContext(gomock.Any()).
+		Return(map[string]string{
+			"shipit":  "https://emoji.slack-edge.com/T123/shipit/abc.png",
+			"ship-it": "alias:shipit",
+		}, nil).
+		Times(1)
+
+	emoji, err := mockSlack.GetEmojiContext(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, emoji, 2)
+	assert.Equal(t, "alias:shipit", emoji["ship-it"])
+}
+
+func TestAddReactionContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+	item := slack.ItemRef{Channel: "C123456", Timestamp: "1738144800.000100"}
+
+	mockSlack.EXPECT().
+		AddReactionContext(gomock.Any(), "bug", item).
+		Return(nil).
+		Times(1)
+
+	err := mockSlack.AddReactionContext(context.Background(), "bug", item)
+	assert.NoError(t, err)
+}
+
+func TestUpdateMessageContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		UpdateMessageContext(gomock.Any(), "C123456", "1738144800.000200", gomock.Any()).
+		Return("C123456", "1738144800.000200", "Categorized", nil).
+		Times(1)
+
+	_, timestamp, _, err := mockSlack.UpdateMessageContext(context.Background(), "C123456", "1738144800.000200", slack.MsgOptionText("Categorized", false))
+	assert.NoError(t, err)
+	assert.Equal(t, "1738144800.000200", timestamp)
+}
+
+func TestAuthTestContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSlack := NewMockClient(ctrl)
+
+	mockSlack.EXPECT().
+		AuthTestContext(gomock.Any()).
+		Return(&slack.AuthTestResponse{UserID: "U0BOT", BotID: "B0BOT"}, nil).
+		Times(1)
+
+	response, err := mockSlack.AuthTestContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "U0BOT", response.UserID)
+}
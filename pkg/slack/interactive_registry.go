@@ -20,6 +20,7 @@ type InteractiveRegistry struct {
 func NewInteractiveRegistry() *InteractiveRegistry {
 	handlers := make(InteractionHandlers)
 	handlers[slack.InteractionTypeShortcut] = make(HandlerMap)
+	handlers[slack.InteractionTypeMessageAction] = make(HandlerMap)
 
 	return &InteractiveRegistry{
 		handlers: handlers,
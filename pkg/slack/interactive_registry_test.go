@@ -15,6 +15,7 @@ func TestNewInteractiveRegistry(t *testing.T) {
 	assert.NotNil(t, registry, "Registry should not be nil")
 	assert.NotNil(t, registry.handlers, "Handlers map should be initialized")
 	assert.NotNil(t, registry.handlers[slack.InteractionTypeShortcut], "Shortcut handlers should be initialized")
+	assert.NotNil(t, registry.handlers[slack.InteractionTypeMessageAction], "Message action handlers should be initialized")
 }
 
 // Mock handler function
@@ -109,3 +110,21 @@ func TestDispatchBlockActions(t *testing.T) {
 	assert.NoError(t, err, "Expected dispatch to succeed")
 	assert.True(t, called, "Handler should have been called")
 }
+
+// TestDispatchMessageAction ensures message shortcuts are dispatched by their callback ID
+func TestDispatchMessageAction(t *testing.T) {
+	registry := NewInteractiveRegistry()
+
+	called := false
+	registry.Register(slack.InteractionTypeMessageAction, "categorize_message", func(interType slack.InteractionType, payload slack.InteractionCallback) error {
+		called = true
+		assert.Equal(t, "1738144800.000100", payload.Message.Timestamp)
+		return nil
+	})
+
+	payload := slack.InteractionCallback{CallbackID: "categorize_message"}
+	payload.Message.Timestamp = "1738144800.000100"
+	err := registry.Dispatch(context.Background(), slack.InteractionTypeMessageAction, payload)
+	assert.NoError(t, err)
+	assert.True(t, called, "Handler should be called")
+}
@@ -36,6 +36,35 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// AddReactionContext mocks base method.
+func (m *MockClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReactionContext", ctx, name, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReactionContext indicates an expected call of AddReactionContext.
+func (mr *MockClientMockRecorder) AddReactionContext(ctx, name, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReactionContext", reflect.TypeOf((*MockClient)(nil).AddReactionContext), ctx, name, item)
+}
+
+// AuthTestContext mocks base method.
+func (m *MockClient) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthTestContext", ctx)
+	ret0, _ := ret[0].(*slack.AuthTestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthTestContext indicates an expected call of AuthTestContext.
+func (mr *MockClientMockRecorder) AuthTestContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthTestContext", reflect.TypeOf((*MockClient)(nil).AuthTestContext), ctx)
+}
+
 // FetchMessages mocks base method.
 func (m *MockClient) FetchMessages(ctx context.Context, channelID string, from, to time.Time) ([]slack.Message, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +95,51 @@ func (mr *MockClientMockRecorder) FetchReactions(ctx, channelID, timestamp inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchReactions", reflect.TypeOf((*MockClient)(nil).FetchReactions), ctx, channelID, timestamp)
 }
 
+// FetchReplies mocks base method.
+func (m *MockClient) FetchReplies(ctx context.Context, channelID, threadTS string) ([]slack.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchReplies", ctx, channelID, threadTS)
+	ret0, _ := ret[0].([]slack.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchReplies indicates an expected call of FetchReplies.
+func (mr *MockClientMockRecorder) FetchReplies(ctx, channelID, threadTS interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchReplies", reflect.TypeOf((*MockClient)(nil).FetchReplies), ctx, channelID, threadTS)
+}
+
+// GetEmojiContext mocks base method.
+func (m *MockClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmojiContext", ctx)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmojiContext indicates an expected call of GetEmojiContext.
+func (mr *MockClientMockRecorder) GetEmojiContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmojiContext", reflect.TypeOf((*MockClient)(nil).GetEmojiContext), ctx)
+}
+
+// GetPermalinkContext mocks base method.
+func (m *MockClient) GetPermalinkContext(ctx context.Context, params *slack.PermalinkParameters) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermalinkContext", ctx, params)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermalinkContext indicates an expected call of GetPermalinkContext.
+func (mr *MockClientMockRecorder) GetPermalinkContext(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermalinkContext", reflect.TypeOf((*MockClient)(nil).GetPermalinkContext), ctx, params)
+}
+
 // ListenEvents mocks base method.
 func (m *MockClient) ListenEvents(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -189,6 +263,28 @@ func (mr *MockClientMockRecorder) RegisterInteractiveHandler(interactionType, ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterInteractiveHandler", reflect.TypeOf((*MockClient)(nil).RegisterInteractiveHandler), interactionType, callbackID, handler)
 }
 
+// UpdateMessageContext mocks base method.
+func (m *MockClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, channelID, timestamp}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateMessageContext", varargs...)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(string)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// UpdateMessageContext indicates an expected call of UpdateMessageContext.
+func (mr *MockClientMockRecorder) UpdateMessageContext(ctx, channelID, timestamp interface{}, options ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, channelID, timestamp}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageContext", reflect.TypeOf((*MockClient)(nil).UpdateMessageContext), varargs...)
+}
+
 // UploadFileV2Context mocks base method.
 func (m *MockClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
 	m.ctrl.T.Helper()
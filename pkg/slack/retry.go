@@ -0,0 +1,97 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// RetryPolicy controls how failed Slack API calls are retried.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt
+	BaseDelay  time.Duration // backoff before the first retry, doubled on every retry
+	MaxDelay   time.Duration // upper bound of the backoff
+}
+
+// DefaultRetryPolicy is used by the Slack client for reading history and reactions.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't worth retrying,
+// runs out of retries or the context is canceled.
+func (p RetryPolicy) Do(ctx context.Context, operation string, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 0 {
+				log.Printf("%s succeeded after %d retries", operation, attempt)
+			}
+			return nil
+		}
+
+		delay, retryable := p.delay(err, attempt)
+		if !retryable {
+			return err
+		}
+		if attempt >= p.MaxRetries {
+			return fmt.Errorf("%s failed after %d retries: %w", operation, attempt, err)
+		}
+
+		log.Printf("%s failed, retry %d/%d in %s: %v", operation, attempt+1, p.MaxRetries, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// delay returns how long to wait before retrying after err, and whether err
+// is worth retrying at all.
+func (p RetryPolicy) delay(err error, attempt int) (time.Duration, bool) {
+	// Slack tells exactly when the next call is allowed
+	var rateLimited *slack.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		return rateLimited.RetryAfter + jitter(p.BaseDelay), true
+	}
+	if !isTransient(err) {
+		return 0, false
+	}
+
+	backoff := p.BaseDelay << attempt
+	if backoff <= 0 || backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	// equal jitter: keep half of the backoff, randomize the other half
+	return backoff/2 + jitter(backoff/2), true
+}
+
+// isTransient reports whether err is a temporary failure, like a server error or a timeout.
+func isTransient(err error) bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// jitter returns a random duration in [0, max).
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
@@ -0,0 +1,89 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   5 * time.Millisecond,
+}
+
+// TestRetryTransientError ensures server errors are retried until the call succeeds
+func TestRetryTransientError(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.Do(context.Background(), "test", func() error {
+		calls++
+		if calls < 3 {
+			return slack.StatusCodeError{Code: http.StatusBadGateway, Status: "502 Bad Gateway"}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls, "Call should be retried until it succeeds")
+}
+
+// TestRetryRateLimited ensures Retry-After is honored
+func TestRetryRateLimited(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := testRetryPolicy.Do(context.Background(), "test", func() error {
+		calls++
+		if calls == 1 {
+			return &slack.RateLimitedError{RetryAfter: 20 * time.Millisecond}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond, "Retry should wait for Retry-After")
+}
+
+// TestRetryPermanentError ensures non-transient errors are returned right away
+func TestRetryPermanentError(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.Do(context.Background(), "test", func() error {
+		calls++
+		return slack.SlackErrorResponse{Err: "channel_not_found"}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "Permanent errors should not be retried")
+}
+
+// TestRetryExhausted ensures the last error is returned when retries run out
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.Do(context.Background(), "test", func() error {
+		calls++
+		return slack.StatusCodeError{Code: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 retries")
+	assert.Equal(t, 4, calls, "Call should be attempted once plus MaxRetries times")
+	var statusErr slack.StatusCodeError
+	assert.True(t, errors.As(err, &statusErr), "Original error should be wrapped")
+}
+
+// TestRetryContextCanceled ensures waiting stops when the context is canceled
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := testRetryPolicy.Do(ctx, "test", func() error {
+		return &slack.RateLimitedError{RetryAfter: time.Minute}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,45 @@
+package storage
+
+import "time"
+
+// CategoryTree maps categories to their parent category. Root categories
+// have no entry.
+type CategoryTree map[string]string
+
+// Path returns the ancestors of the category from the root down, followed by
+// the category itself.
+func (t CategoryTree) Path(category string) []string {
+	path := []string{category}
+	seen := map[string]bool{category: true}
+	for parent, ok := t[category]; ok && parent != "" && !seen[parent]; parent, ok = t[parent] {
+		seen[parent] = true
+		path = append([]string{parent}, path...)
+	}
+	return path
+}
+
+// Depth returns the number of ancestors of the category, 0 for root categories.
+func (t CategoryTree) Depth(category string) int {
+	return len(t.Path(category)) - 1
+}
+
+// HasChildren reports whether any category has the given one as its parent.
+func (t CategoryTree) HasChildren(category string) bool {
+	for _, parent := range t {
+		if parent == category {
+			return true
+		}
+	}
+	return false
+}
+
+// RollUpQuery defines how leaf category stats are rolled up the category tree
+type RollUpQuery struct {
+	Channel string
+	Start   time.Time
+	End     time.Time
+	Tree    CategoryTree
+	Level   int                 // depth categories are rolled up to, 0 rolls everything up to the roots
+	Parent  string              // only the categories under this one, all categories if empty
+	Filter  func(*Request) bool // requests that count toward the stats, all of them if nil
+}
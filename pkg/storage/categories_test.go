@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryTree(t *testing.T) {
+	tree := CategoryTree{
+		"Infra bug": "Infra",
+		"Capacity":  "Infra",
+		"Infra":     "Engineering",
+		"Loop A":    "Loop B",
+		"Loop B":    "Loop A",
+	}
+
+	assert.Equal(t, []string{"Engineering", "Infra", "Infra bug"}, tree.Path("Infra bug"))
+	assert.Equal(t, []string{"CI/CD"}, tree.Path("CI/CD"), "Unknown categories are roots")
+	assert.Equal(t, 2, tree.Depth("Capacity"))
+	assert.Equal(t, 0, tree.Depth("Engineering"))
+	assert.Len(t, tree.Path("Loop A"), 2, "Cycles should not hang")
+
+	assert.True(t, tree.HasChildren("Infra"))
+	assert.False(t, tree.HasChildren("Capacity"))
+}
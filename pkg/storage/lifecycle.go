@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// ElapsedFunc measures the time between two events, e.g. in business hours.
+type ElapsedFunc func(from, to time.Time) time.Duration
+
+// LifecycleStats are the categorization, response and resolution times of the
+// requests of a category. Durations are zero when no request reached that stage.
+// Acks and resolutions found by a sync count, but their times are unknown, so
+// they are left out of the durations.
+type LifecycleStats struct {
+	Category             string
+	Requests             int // requests in the category
+	Categorized          int // requests with a reaction a rule matched
+	Acked                int // requests with an ack reaction
+	Resolved             int // requests with a resolution reaction
+	MedianCategorization time.Duration
+	P90Categorization    time.Duration
+	MedianResponse       time.Duration
+	P90Response          time.Duration
+	MedianResolution     time.Duration
+	P90Resolution        time.Duration
+}
+
+// lifecycleDurations collects the lifecycle durations of the requests of a category.
+type lifecycleDurations struct {
+	requests        int
+	acked           int
+	resolved        int
+	categorizations []time.Duration
+	responses       []time.Duration
+	resolutions     []time.Duration
+}
+
+// lifecycleStats computes the lifecycle stats per category, sorted by
+// category. Durations are wall clock time if elapsed is nil.
+func lifecycleStats(requests []Request, elapsed ElapsedFunc) []LifecycleStats {
+	if elapsed == nil {
+		elapsed = func(from, to time.Time) time.Duration { return to.Sub(from) }
+	}
+
+	durations := make(map[string]*lifecycleDurations)
+	for _, request := range requests {
+		for _, category := range request.Categories {
+			d := durations[category.Category]
+			if d == nil {
+				d = &lifecycleDurations{}
+				durations[category.Category] = d
+			}
+			d.requests++
+			if request.CategorizedAt != nil {
+				d.categorizations = append(d.categorizations, elapsed(request.PostedAt, *request.CategorizedAt))
+			}
+			if request.AckedAt != nil {
+				d.acked++
+				if !request.AckSynced {
+					d.responses = append(d.responses, elapsed(request.PostedAt, *request.AckedAt))
+				}
+			}
+			if request.ResolvedAt != nil {
+				d.resolved++
+				if !request.ResolutionSynced {
+					d.resolutions = append(d.resolutions, elapsed(request.PostedAt, *request.ResolvedAt))
+				}
+			}
+		}
+	}
+
+	results := make([]LifecycleStats, 0, len(durations))
+	for _, category := range sortedCategories(durations) {
+		d := durations[category]
+		results = append(results, LifecycleStats{
+			Category:             category,
+			Requests:             d.requests,
+			Categorized:          len(d.categorizations),
+			Acked:                d.acked,
+			Resolved:             d.resolved,
+			MedianCategorization: Percentile(d.categorizations, 50),
+			P90Categorization:    Percentile(d.categorizations, 90),
+			MedianResponse:       Percentile(d.responses, 50),
+			P90Response:          Percentile(d.responses, 90),
+			MedianResolution:     Percentile(d.resolutions, 50),
+			P90Resolution:        Percentile(d.resolutions, 90),
+		})
+	}
+	return results
+}
+
+// Percentile returns the p-th percentile of the durations using the
+// nearest-rank method, zero for no durations.
+func Percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	// nearest rank: ceil(p/100 * n), 1-based
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	durations := []time.Duration{
+		10 * time.Minute, time.Minute, 3 * time.Minute, 2 * time.Minute, 5 * time.Minute,
+		4 * time.Minute, 7 * time.Minute, 6 * time.Minute, 9 * time.Minute, 8 * time.Minute,
+	}
+
+	assert.Equal(t, 5*time.Minute, Percentile(durations, 50))
+	assert.Equal(t, 9*time.Minute, Percentile(durations, 90))
+	assert.Equal(t, 10*time.Minute, Percentile(durations, 100))
+	assert.Equal(t, time.Minute, Percentile(durations, 0))
+	assert.Equal(t, time.Duration(0), Percentile(nil, 50))
+	assert.Equal(t, time.Minute, durations[1], "Input should not be reordered")
+}
+
+func TestLifecycleStats_Elapsed(t *testing.T) {
+	postedAt := time.Date(2025, 01, 31, 16, 0, 0, 0, time.UTC)
+	categorizedAt := postedAt.Add(66 * time.Hour)
+	requests := []Request{
+		{PostedAt: postedAt, CategorizedAt: &categorizedAt, Categories: []RequestCategory{{Category: "Infra Bug"}}},
+	}
+	// e.g. business hours, which skip the weekend
+	elapsed := func(from, to time.Time) time.Duration { return to.Sub(from) - 48*time.Hour }
+
+	stats := lifecycleStats(requests, elapsed)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, 1, stats[0].Categorized)
+	assert.Equal(t, 18*time.Hour, stats[0].MedianCategorization)
+	assert.Equal(t, 0, stats[0].Acked)
+	assert.Equal(t, 66*time.Hour, lifecycleStats(requests, nil)[0].MedianCategorization)
+}
+
+func TestLifecycleStats_Synced(t *testing.T) {
+	postedAt := time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC)
+	ackedAt, resolvedAt := postedAt.Add(10*time.Minute), postedAt.Add(time.Hour)
+	syncedAt := postedAt.Add(72 * time.Hour)
+	requests := []Request{
+		{PostedAt: postedAt, AckedAt: &ackedAt, ResolvedAt: &resolvedAt,
+			Categories: []RequestCategory{{Category: "Infra Bug"}}},
+		{PostedAt: postedAt, AckedAt: &syncedAt, AckSynced: true, ResolvedAt: &syncedAt, ResolutionSynced: true,
+			Categories: []RequestCategory{{Category: "Infra Bug"}}},
+	}
+
+	stats := lifecycleStats(requests, nil)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, 2, stats[0].Acked, "Synced acks should count")
+	assert.Equal(t, 2, stats[0].Resolved, "Synced resolutions should count")
+	assert.Equal(t, 10*time.Minute, stats[0].P90Response, "Synced times should be left out of the durations")
+	assert.Equal(t, time.Hour, stats[0].P90Resolution)
+}
@@ -9,9 +9,128 @@ type Stats struct {
 	Channel  string    `gorm:"not null;uniqueIndex:idx_stats_unique"`
 	Category string    `gorm:"not null;uniqueIndex:idx_stats_unique"`
 	Reaction string    `gorm:"not null;default:''"`
-	Count    int       `gorm:"default:0"`
+	Count    int       `gorm:"default:0"` // categorized reactions
+	Requests int       `gorm:"default:0"` // distinct requests
 	Date     time.Time `gorm:"type:DATE;not null;uniqueIndex:idx_stats_unique"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// CategoryStats are the counts of a category on a single day.
+type CategoryStats struct {
+	Requests  int // distinct requests in the category
+	Reactions int // reactions that put the requests in the category
+}
+
+// Request is a single message posted in a tracked channel.
+type Request struct {
+	ID               uint      `gorm:"primaryKey"`
+	Channel          string    `gorm:"not null;uniqueIndex:idx_request_unique"`
+	MessageTS        string    `gorm:"not null;uniqueIndex:idx_request_unique"`
+	Author           string    `gorm:"not null;default:''"`
+	Assignee         string    `gorm:"not null;default:'';index"` // user on call when the request was beaconed
+	Permalink        string    `gorm:"not null;default:''"`
+	Text             string    `gorm:"not null;default:''"`
+	PostedAt         time.Time `gorm:"not null;index"`
+	BeaconAt         *time.Time
+	FirstReactionAt  *time.Time
+	CategorizedAt    *time.Time          // first reaction a rule matched
+	AckedAt          *time.Time          // first ack reaction
+	ResolvedAt       *time.Time          // resolution reaction, cleared when it's taken back
+	AckSynced        bool                `gorm:"not null;default:false"` // AckedAt is when a sync found the ack, not when it was added
+	ResolutionSynced bool                `gorm:"not null;default:false"` // ResolvedAt is when a sync found the resolution, not when it was added
+	ActiveAt         *time.Time          // last reaction change or thread reply
+	RemindedAt       *time.Time          // last stale request reminder
+	Reminders        int                 `gorm:"not null;default:0"`  // stale request reminders posted
+	Reactions        map[string]int      `gorm:"serializer:json"`     // reaction name -> count
+	Reactors         map[string][]string `gorm:"serializer:json"`     // reaction as Slack names it -> who added it, nil for requests stored before it was tracked
+	ManualCategory   string              `gorm:"not null;default:''"` // category picked by a person, overrides the rules
+	CategorizedBy    string              `gorm:"not null;default:''"` // user who picked the manual category
+	CategoryNote     string              `gorm:"not null;default:''"` // why the manual category was picked
+	TicketKey        string              `gorm:"not null;default:''"` // issue tracker ticket created from the request, e.g. "OPS-42"
+	TicketURL        string              `gorm:"not null;default:''"`
+	Categories       []RequestCategory   `gorm:"constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RequestCategory is a category assigned to a request.
+type RequestCategory struct {
+	ID        uint   `gorm:"primaryKey"`
+	RequestID uint   `gorm:"not null;uniqueIndex:idx_request_category_unique"`
+	Category  string `gorm:"not null;uniqueIndex:idx_request_category_unique"`
+	Count     int    `gorm:"default:0"` // number of reactions that put the request in this category
+	Source    string `gorm:"not null;default:'reaction'"`
+}
+
+// Sources of request categories.
+const (
+	CategorySourceReaction      = "reaction"      // a reaction rule matched a reaction on the message
+	CategorySourceText          = "text"          // a text rule matched the message text
+	CategorySourceUncategorized = "uncategorized" // no rule matched the request
+	CategorySourceManual        = "manual"        // a person picked the category
+)
+
+// CategoryNames returns the names of the categories assigned to the request.
+func (r *Request) CategoryNames() []string {
+	names := make([]string, 0, len(r.Categories))
+	for _, category := range r.Categories {
+		names = append(names, category.Category)
+	}
+	return names
+}
+
+// SyncCheckpoint remembers the newest message synced from a channel.
+type SyncCheckpoint struct {
+	Channel   string `gorm:"primaryKey"`
+	MessageTS string `gorm:"not null"`
+
+	UpdatedAt time.Time
+}
+
+// SyncFailure is a message the history sync couldn't process, retried on
+// later syncs so it doesn't hold the channel checkpoint back.
+type SyncFailure struct {
+	Channel   string `gorm:"primaryKey"`
+	MessageTS string `gorm:"primaryKey"`
+	Attempts  int    `gorm:"not null;default:0"`
+	Error     string `gorm:"not null;default:''"`
+
+	UpdatedAt time.Time
+}
+
+// SLABreach is a request that missed an SLA target of its category.
+type SLABreach struct {
+	ID         uint          `gorm:"primaryKey"`
+	RequestID  uint          `gorm:"not null;uniqueIndex:idx_sla_breach_unique"`
+	Kind       string        `gorm:"not null;uniqueIndex:idx_sla_breach_unique"` // one of the SLAKind* values
+	Channel    string        `gorm:"not null;index"`
+	Category   string        `gorm:"not null"`
+	Target     time.Duration `gorm:"not null"`
+	DueAt      time.Time     `gorm:"not null;index"`
+	DetectedAt time.Time     `gorm:"not null"`
+	Notified   bool          `gorm:"not null;default:false"` // whether an alert was posted
+
+	CreatedAt time.Time
+}
+
+// SLA target kinds.
+const (
+	SLAKindAck     = "ack"     // the request wasn't acked in time
+	SLAKindResolve = "resolve" // the request wasn't resolved in time
+)
+
+// WebhookFailure is an outgoing webhook delivery that failed after all retries.
+type WebhookFailure struct {
+	ID         uint   `gorm:"primaryKey"`
+	URL        string `gorm:"not null;index"`
+	Event      string `gorm:"not null"`
+	DeliveryID string `gorm:"not null"`
+	Payload    string `gorm:"not null"` // JSON body, to redeliver by hand
+	Attempts   int    `gorm:"not null"`
+	Error      string `gorm:"not null;default:''"`
+
+	CreatedAt time.Time
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -8,11 +9,39 @@ import (
 
 // StatsRepository defines methods for interacting with stats storage
 type StatsRepository interface {
-	SaveStats(channelID string, date time.Time, stats map[string]int) error
+	SaveStats(channelID string, date time.Time, stats map[string]CategoryStats) error
+	IncrementStats(channelID string, date time.Time, stats map[string]CategoryStats) error
+	DecrementStats(channelID string, date time.Time, stats map[string]CategoryStats) error
+	ReplaceStats(channelID string, date time.Time, stats map[string]CategoryStats) error
 	GetAggregatedStats(channel string, start, end time.Time) ([]Stats, error)
 	GetDailyStats(channel string, start, end time.Time) ([]Stats, error)
+	GetRolledUpStats(query RollUpQuery) ([]Stats, error)
+	GetRequestCounts(channel string, start, end time.Time) (map[string]int, error)
+	GetReactionCounts(channel string, start, end time.Time) (map[string]int, error)
+
+	SaveRequest(request *Request) error
+	GetRequest(channel, messageTS string) (*Request, error)
+	ListRequests(query RequestQuery) ([]Request, error)
+	GetLifecycleStats(query RequestQuery, elapsed ElapsedFunc) ([]LifecycleStats, error)
+	GetWorkload(query RequestQuery) ([]Workload, error)
+
+	RecordSLABreach(breach *SLABreach) (bool, error)
+	MarkSLABreachNotified(id uint) error
+	ListSLABreaches(query SLABreachQuery) ([]SLABreach, error)
+
+	RecordWebhookFailure(failure *WebhookFailure) error
+	ListWebhookFailures(limit int) ([]WebhookFailure, error)
+
+	GetSyncCheckpoint(channel string) (string, error)
+	SaveSyncCheckpoint(channel, messageTS string) error
+	RecordSyncFailure(channel, messageTS, reason string) error
+	ListSyncFailures(channel string, maxAttempts int) ([]SyncFailure, error)
+	DeleteSyncFailure(channel, messageTS string) error
 }
 
+// ErrRequestNotFound is returned when a request is not stored yet.
+var ErrRequestNotFound = errors.New("request not found")
+
 // NewRepository initializes the database and returns a StatsRepository.
 func NewRepository(driver, dsn string) (StatsRepository, error) {
 	db, err := InitDB(driver, dsn)
@@ -20,7 +49,7 @@ func NewRepository(driver, dsn string) (StatsRepository, error) {
 		return nil, err
 	}
 
-	err = Migrate(db, &Stats{})
+	err = Migrate(db, &Stats{}, &Request{}, &RequestCategory{}, &SyncCheckpoint{}, &SyncFailure{}, &SLABreach{}, &WebhookFailure{})
 	if err != nil {
 		return nil, err
 	}
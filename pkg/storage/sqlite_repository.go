@@ -3,6 +3,8 @@ package storage
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +20,28 @@ type StatsQuery struct {
 	GroupBy []string // Defines what fields to group by (category, date, etc.)
 }
 
+// RequestQuery defines filters for listing requests, zero values are ignored
+type RequestQuery struct {
+	Channel     string
+	Category    string
+	Start       time.Time
+	End         time.Time
+	NotAcked    bool                // only requests without an ack
+	NotResolved bool                // only requests that aren't resolved
+	Beaconed    bool                // only requests that got the beacon reaction
+	Filter      func(*Request) bool // only requests it accepts, applied after Limit
+	Limit       int
+}
+
+// SLABreachQuery defines filters for listing SLA breaches, zero values are ignored
+type SLABreachQuery struct {
+	Channel     string
+	Category    string
+	Start       time.Time // earliest due time
+	End         time.Time // latest due time
+	NotNotified bool      // only breaches no alert was posted for
+}
+
 // SQLiteStatsRepository is the SQLite implementation of StatsRepository
 type SQLiteStatsRepository struct {
 	DB *gorm.DB
@@ -43,12 +67,12 @@ func (r *SQLiteStatsRepository) getStats(query StatsQuery) ([]Stats, error) {
 	db := r.DB
 
 	// Base selection
-	db = db.Select("SUM(count) as count")
+	db = db.Select("SUM(count) as count, SUM(requests) as requests")
 
 	// Append group fields dynamically
 	if len(query.GroupBy) > 0 {
 		groupClause := strings.Join(query.GroupBy, ", ")
-		db = db.Select(groupClause + ", SUM(count) as count").Group(groupClause)
+		db = db.Select(groupClause + ", SUM(count) as count, SUM(requests) as requests").Group(groupClause)
 	}
 
 	// Apply filters
@@ -72,14 +96,103 @@ func (r *SQLiteStatsRepository) GetDailyStats(channel string, start, end time.Ti
 	return r.getStats(query)
 }
 
-func (r *SQLiteStatsRepository) SaveStats(channelID string, date time.Time, stats map[string]int) error {
+// GetRolledUpStats returns the stats of the interval with every category
+// counted under its ancestor at the query level, sorted by category.
+// Reactions are summed up, requests are counted once per ancestor from the
+// stored requests, so a request in several categories under the same
+// ancestor counts once.
+func (r *SQLiteStatsRepository) GetRolledUpStats(query RollUpQuery) ([]Stats, error) {
+	stats, err := r.GetAggregatedStats(query.Channel, query.Start, query.End)
+	if err != nil {
+		return nil, err
+	}
+
+	// ancestor returns the category the given one is counted under, false if
+	// it's outside the queried parent
+	ancestor := func(category string) (string, bool) {
+		path := query.Tree.Path(category)
+		if query.Parent != "" && !slices.Contains(path, query.Parent) {
+			return "", false
+		}
+		return path[min(max(query.Level, 0), len(path)-1)], true
+	}
+
+	rolledUp := make(map[string]Stats)
+	for _, stat := range stats {
+		category, ok := ancestor(stat.Category)
+		if !ok {
+			continue
+		}
+		total := rolledUp[category]
+		total.Channel = query.Channel
+		total.Category = category
+		total.Count += stat.Count
+		rolledUp[category] = total
+	}
+
+	// requests are counted on the local day they were posted on, like the stats
+	start := time.Date(query.Start.Year(), query.Start.Month(), query.Start.Day(), 0, 0, 0, 0, time.Local)
+	end := time.Date(query.End.Year(), query.End.Month(), query.End.Day()+1, 0, 0, 0, 0, time.Local).Add(-time.Nanosecond)
+	requests, err := r.ListRequests(RequestQuery{Channel: query.Channel, Start: start, End: end, Filter: query.Filter})
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		counted := make(map[string]bool)
+		for _, requestCategory := range request.Categories {
+			category, ok := ancestor(requestCategory.Category)
+			if !ok || counted[category] {
+				continue
+			}
+			counted[category] = true
+			total := rolledUp[category]
+			total.Channel = query.Channel
+			total.Category = category
+			total.Requests++
+			rolledUp[category] = total
+		}
+	}
+
+	results := make([]Stats, 0, len(rolledUp))
+	for _, category := range sortedCategories(rolledUp) {
+		results = append(results, rolledUp[category])
+	}
+	return results, nil
+}
+
+// GetRequestCounts returns the number of distinct requests per category in the interval.
+func (r *SQLiteStatsRepository) GetRequestCounts(channel string, start, end time.Time) (map[string]int, error) {
+	return r.countsByCategory(channel, start, end, func(stat Stats) int { return stat.Requests })
+}
+
+// GetReactionCounts returns the number of categorized reactions per category in the interval.
+func (r *SQLiteStatsRepository) GetReactionCounts(channel string, start, end time.Time) (map[string]int, error) {
+	return r.countsByCategory(channel, start, end, func(stat Stats) int { return stat.Count })
+}
+
+func (r *SQLiteStatsRepository) countsByCategory(channel string, start, end time.Time, count func(Stats) int) (map[string]int, error) {
+	stats, err := r.GetAggregatedStats(channel, start, end)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		counts[stat.Category] = count(stat)
+	}
+	return counts, nil
+}
+
+// SaveStats sets the counts of the given categories on the day, leaving
+// other categories alone.
+func (r *SQLiteStatsRepository) SaveStats(channelID string, date time.Time, stats map[string]CategoryStats) error {
 	tx := r.DB.Begin()
 
-	for category, count := range stats {
+	for _, category := range sortedCategories(stats) {
 		stat := Stats{
 			Channel:   channelID,
 			Category:  category,
-			Count:     count,
+			Count:     stats[category].Reactions,
+			Requests:  stats[category].Requests,
 			Date:      date,
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -87,7 +200,7 @@ func (r *SQLiteStatsRepository) SaveStats(channelID string, date time.Time, stat
 
 		err := tx.Clauses(clause.OnConflict{
 			Columns:   []clause.Column{{Name: "channel"}, {Name: "category"}, {Name: "date"}},
-			DoUpdates: clause.AssignmentColumns([]string{"count", "updated_at"}),
+			DoUpdates: clause.AssignmentColumns([]string{"count", "requests", "updated_at"}),
 		}).Create(&stat).Error
 
 		if err != nil {
@@ -98,3 +211,335 @@ func (r *SQLiteStatsRepository) SaveStats(channelID string, date time.Time, stat
 
 	return tx.Commit().Error
 }
+
+// IncrementStats adds the given counts to the stored counts of the day,
+// creating the rows that don't exist yet.
+func (r *SQLiteStatsRepository) IncrementStats(channelID string, date time.Time, stats map[string]CategoryStats) error {
+	return r.adjustStats(channelID, date, stats, 1)
+}
+
+// DecrementStats subtracts the given counts from the stored counts of the day.
+// Counts never go below zero, missing rows are created with a zero count.
+func (r *SQLiteStatsRepository) DecrementStats(channelID string, date time.Time, stats map[string]CategoryStats) error {
+	return r.adjustStats(channelID, date, stats, -1)
+}
+
+// adjustStats upserts the stats of the day, adding sign*counts to every category.
+func (r *SQLiteStatsRepository) adjustStats(channelID string, date time.Time, stats map[string]CategoryStats, sign int) error {
+	tx := r.DB.Begin()
+
+	for _, category := range sortedCategories(stats) {
+		reactions := sign * stats[category].Reactions
+		requests := sign * stats[category].Requests
+		stat := Stats{
+			Channel:   channelID,
+			Category:  category,
+			Count:     max(reactions, 0),
+			Requests:  max(requests, 0),
+			Date:      date,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "channel"}, {Name: "category"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":      gorm.Expr("CASE WHEN count + ? > 0 THEN count + ? ELSE 0 END", reactions, reactions),
+				"requests":   gorm.Expr("CASE WHEN requests + ? > 0 THEN requests + ? ELSE 0 END", requests, requests),
+				"updated_at": stat.UpdatedAt,
+			}),
+		}).Create(&stat).Error
+
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update stats: %w", err)
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// ReplaceStats overwrites all stats of the day with the given counts.
+func (r *SQLiteStatsRepository) ReplaceStats(channelID string, date time.Time, stats map[string]CategoryStats) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("channel = ? AND date = ?", channelID, date).Delete(&Stats{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to clear stats: %w", err)
+		}
+
+		for _, category := range sortedCategories(stats) {
+			stat := Stats{
+				Channel:  channelID,
+				Category: category,
+				Count:    stats[category].Reactions,
+				Requests: stats[category].Requests,
+				Date:     date,
+			}
+			if err := tx.Create(&stat).Error; err != nil {
+				return fmt.Errorf("failed to save stats: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// sortedCategories returns the categories of the stats map in a stable order,
+// so rows are always written in the same sequence.
+func sortedCategories[V any](stats map[string]V) []string {
+	categories := make([]string, 0, len(stats))
+	for category := range stats {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// SaveRequest creates or updates the request identified by its channel and
+// message timestamp, replacing its categories.
+func (r *SQLiteStatsRepository) SaveRequest(request *Request) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []Request
+		err := tx.Select("id", "created_at").
+			Where("channel = ? AND message_ts = ?", request.Channel, request.MessageTS).
+			Limit(1).Find(&existing).Error
+		if err != nil {
+			return fmt.Errorf("failed to look up request: %w", err)
+		}
+		request.ID = 0
+		if len(existing) > 0 {
+			request.ID = existing[0].ID
+			request.CreatedAt = existing[0].CreatedAt
+		}
+
+		request.PostedAt = request.PostedAt.UTC()
+		if err := tx.Omit("Categories").Save(request).Error; err != nil {
+			return fmt.Errorf("failed to save request: %w", err)
+		}
+
+		if err := tx.Where("request_id = ?", request.ID).Delete(&RequestCategory{}).Error; err != nil {
+			return fmt.Errorf("failed to reset request categories: %w", err)
+		}
+		for i := range request.Categories {
+			request.Categories[i].ID = 0
+			request.Categories[i].RequestID = request.ID
+		}
+		if len(request.Categories) > 0 {
+			if err := tx.Create(&request.Categories).Error; err != nil {
+				return fmt.Errorf("failed to save request categories: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetRequest returns the request for the given message, or ErrRequestNotFound.
+func (r *SQLiteStatsRepository) GetRequest(channel, messageTS string) (*Request, error) {
+	var requests []Request
+	err := r.DB.Preload("Categories").
+		Where("channel = ? AND message_ts = ?", channel, messageTS).
+		Limit(1).Find(&requests).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch request: %w", err)
+	}
+	if len(requests) == 0 {
+		return nil, ErrRequestNotFound
+	}
+	return &requests[0], nil
+}
+
+// ListRequests returns the requests matching the query, oldest first.
+func (r *SQLiteStatsRepository) ListRequests(query RequestQuery) ([]Request, error) {
+	var results []Request
+	db := r.DB.Preload("Categories").Order("posted_at")
+
+	if query.Channel != "" {
+		db = db.Where("channel = ?", query.Channel)
+	}
+	if query.Category != "" {
+		db = db.Where("id IN (?)", r.DB.Model(&RequestCategory{}).Select("request_id").Where("category = ?", query.Category))
+	}
+	if !query.Start.IsZero() {
+		db = db.Where("posted_at >= ?", query.Start.UTC())
+	}
+	if !query.End.IsZero() {
+		db = db.Where("posted_at <= ?", query.End.UTC())
+	}
+	if query.NotAcked {
+		db = db.Where("acked_at IS NULL")
+	}
+	if query.NotResolved {
+		db = db.Where("resolved_at IS NULL")
+	}
+	if query.Beaconed {
+		db = db.Where("beacon_at IS NOT NULL")
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	err := db.Find(&results).Error
+	if err != nil {
+		log.Printf("❌ Failed to fetch requests: %v", err)
+		return results, err
+	}
+	if query.Filter != nil {
+		results = slices.DeleteFunc(results, func(request Request) bool { return !query.Filter(&request) })
+	}
+	return results, nil
+}
+
+// GetLifecycleStats returns the median and p90 categorization, response and
+// resolution times per category of the requests matching the query, measured
+// with elapsed, or wall clock time if it's nil.
+func (r *SQLiteStatsRepository) GetLifecycleStats(query RequestQuery, elapsed ElapsedFunc) ([]LifecycleStats, error) {
+	// SQLite has no percentile functions, so they are computed from the requests
+	requests, err := r.ListRequests(query)
+	if err != nil {
+		return nil, err
+	}
+	return lifecycleStats(requests, elapsed), nil
+}
+
+// GetWorkload returns how many of the requests matching the query each
+// assignee got, most first.
+func (r *SQLiteStatsRepository) GetWorkload(query RequestQuery) ([]Workload, error) {
+	requests, err := r.ListRequests(query)
+	if err != nil {
+		return nil, err
+	}
+	return workload(requests), nil
+}
+
+// RecordSLABreach stores the breach unless the request already breached the
+// same kind of target. It reports whether the breach is new.
+func (r *SQLiteStatsRepository) RecordSLABreach(breach *SLABreach) (bool, error) {
+	breach.DueAt = breach.DueAt.UTC()
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(breach)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save SLA breach: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkSLABreachNotified records that an alert was posted for the breach.
+func (r *SQLiteStatsRepository) MarkSLABreachNotified(id uint) error {
+	err := r.DB.Model(&SLABreach{}).Where("id = ?", id).Update("notified", true).Error
+	if err != nil {
+		return fmt.Errorf("failed to update SLA breach: %w", err)
+	}
+	return nil
+}
+
+// ListSLABreaches returns the breaches matching the query, oldest first.
+func (r *SQLiteStatsRepository) ListSLABreaches(query SLABreachQuery) ([]SLABreach, error) {
+	var results []SLABreach
+	db := r.DB.Order("due_at")
+
+	if query.Channel != "" {
+		db = db.Where("channel = ?", query.Channel)
+	}
+	if query.Category != "" {
+		db = db.Where("category = ?", query.Category)
+	}
+	if !query.Start.IsZero() {
+		db = db.Where("due_at >= ?", query.Start.UTC())
+	}
+	if !query.End.IsZero() {
+		db = db.Where("due_at <= ?", query.End.UTC())
+	}
+	if query.NotNotified {
+		db = db.Where("notified = ?", false)
+	}
+
+	if err := db.Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch SLA breaches: %w", err)
+	}
+	return results, nil
+}
+
+// GetSyncCheckpoint returns the timestamp of the newest message synced from
+// the channel, or an empty string if the channel was never synced.
+func (r *SQLiteStatsRepository) GetSyncCheckpoint(channel string) (string, error) {
+	var checkpoints []SyncCheckpoint
+	err := r.DB.Where("channel = ?", channel).Limit(1).Find(&checkpoints).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch sync checkpoint: %w", err)
+	}
+	if len(checkpoints) == 0 {
+		return "", nil
+	}
+	return checkpoints[0].MessageTS, nil
+}
+
+// SaveSyncCheckpoint stores the timestamp of the newest message synced from the channel.
+func (r *SQLiteStatsRepository) SaveSyncCheckpoint(channel, messageTS string) error {
+	checkpoint := SyncCheckpoint{Channel: channel, MessageTS: messageTS}
+	err := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"message_ts", "updated_at"}),
+	}).Create(&checkpoint).Error
+	if err != nil {
+		return fmt.Errorf("failed to save sync checkpoint: %w", err)
+	}
+	return nil
+}
+
+// RecordSyncFailure stores a message the sync failed to process, counting
+// the attempts made so far.
+func (r *SQLiteStatsRepository) RecordSyncFailure(channel, messageTS, reason string) error {
+	failure := SyncFailure{Channel: channel, MessageTS: messageTS, Attempts: 1, Error: reason}
+	err := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "channel"}, {Name: "message_ts"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"error":      reason,
+			"updated_at": time.Now(),
+		}),
+	}).Create(&failure).Error
+	if err != nil {
+		return fmt.Errorf("failed to save sync failure: %w", err)
+	}
+	return nil
+}
+
+// ListSyncFailures returns the failed messages of the channel that were
+// attempted fewer than maxAttempts times, oldest first.
+func (r *SQLiteStatsRepository) ListSyncFailures(channel string, maxAttempts int) ([]SyncFailure, error) {
+	var results []SyncFailure
+	err := r.DB.Where("channel = ? AND attempts < ?", channel, maxAttempts).Order("message_ts").Find(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sync failures: %w", err)
+	}
+	return results, nil
+}
+
+// DeleteSyncFailure forgets a failed message once it was processed.
+func (r *SQLiteStatsRepository) DeleteSyncFailure(channel, messageTS string) error {
+	err := r.DB.Where("channel = ? AND message_ts = ?", channel, messageTS).Delete(&SyncFailure{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete sync failure: %w", err)
+	}
+	return nil
+}
+
+// RecordWebhookFailure stores a webhook delivery that failed.
+func (r *SQLiteStatsRepository) RecordWebhookFailure(failure *WebhookFailure) error {
+	if err := r.DB.Create(failure).Error; err != nil {
+		return fmt.Errorf("failed to save webhook failure: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookFailures returns the latest failed webhook deliveries, newest
+// first, all of them if limit is 0.
+func (r *SQLiteStatsRepository) ListWebhookFailures(limit int) ([]WebhookFailure, error) {
+	var results []WebhookFailure
+	db := r.DB.Order("created_at DESC, id DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Find(&results).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook failures: %w", err)
+	}
+	return results, nil
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ func setupTestDB(t *testing.T) *SQLiteStatsRepository {
 	assert.NoError(t, err)
 
 	// Auto-migrate schema
-	err = db.AutoMigrate(&Stats{})
+	err = db.AutoMigrate(&Stats{}, &Request{}, &RequestCategory{}, &SyncCheckpoint{}, &SyncFailure{}, &SLABreach{}, &WebhookFailure{})
 	assert.NoError(t, err)
 
 	return NewSQLiteStatsRepository(db)
@@ -29,7 +30,7 @@ func TestSaveStats(t *testing.T) {
 	// Test Data
 	channelID := "C123"
 	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
-	stats := map[string]int{"CI/CD": 5, "Infra Bug": 2}
+	stats := map[string]CategoryStats{"CI/CD": {Requests: 2, Reactions: 5}, "Infra Bug": {Requests: 1, Reactions: 2}}
 
 	// Save Stats
 	err := repo.SaveStats(channelID, date, stats)
@@ -40,6 +41,7 @@ func TestSaveStats(t *testing.T) {
 	repo.DB.Find(&storedStats)
 	assert.Len(t, storedStats, 2)
 	assert.Equal(t, 5, storedStats[0].Count)
+	assert.Equal(t, 2, storedStats[0].Requests)
 	assert.Equal(t, "CI/CD", storedStats[0].Category)
 }
 
@@ -48,7 +50,7 @@ func TestGetAggregatedStats(t *testing.T) {
 
 	// Insert test data
 	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
-	repo.SaveStats("C123", date, map[string]int{"CI/CD": 3, "Infra Bug": 7})
+	repo.SaveStats("C123", date, map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 3}, "Infra Bug": {Requests: 1, Reactions: 7}})
 
 	// Fetch aggregated stats
 	stats, err := repo.GetAggregatedStats("C123", date, date)
@@ -66,9 +68,9 @@ func TestGetDailyStats(t *testing.T) {
 	repo := setupTestDB(t)
 
 	// Insert data across multiple days
-	repo.SaveStats("C123", time.Date(2025, 01, 28, 0, 0, 0, 0, time.UTC), map[string]int{"CI/CD": 1})
-	repo.SaveStats("C123", time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC), map[string]int{"CI/CD": 2})
-	repo.SaveStats("C123", time.Date(2025, 01, 30, 0, 0, 0, 0, time.UTC), map[string]int{"Infra Bug": 3})
+	repo.SaveStats("C123", time.Date(2025, 01, 28, 0, 0, 0, 0, time.UTC), map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 1}})
+	repo.SaveStats("C123", time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC), map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 2}})
+	repo.SaveStats("C123", time.Date(2025, 01, 30, 0, 0, 0, 0, time.UTC), map[string]CategoryStats{"Infra Bug": {Requests: 1, Reactions: 3}})
 
 	// Fetch daily stats
 	stats, err := repo.GetDailyStats("C123", time.Date(2025, 01, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 01, 30, 0, 0, 0, 0, time.UTC))
@@ -83,3 +85,455 @@ func TestGetDailyStats(t *testing.T) {
 	assert.Equal(t, "Infra Bug", stats[2].Category)
 	assert.Equal(t, 3, stats[2].Count)
 }
+
+func TestIncrementStats(t *testing.T) {
+	repo := setupTestDB(t)
+
+	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
+	err := repo.SaveStats("C123", date, map[string]CategoryStats{"CI/CD": {Reactions: 2}})
+	assert.NoError(t, err)
+
+	// Increment an existing category and create a new one
+	err = repo.IncrementStats("C123", date, map[string]CategoryStats{
+		"CI/CD":     {Requests: 1, Reactions: 1},
+		"Infra Bug": {Requests: 1, Reactions: 2},
+	})
+	assert.NoError(t, err, "Failed to increment stats")
+
+	stats, err := repo.GetAggregatedStats("C123", date, date)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "CI/CD", stats[0].Category)
+	assert.Equal(t, 3, stats[0].Count)
+	assert.Equal(t, 1, stats[0].Requests)
+	assert.Equal(t, "Infra Bug", stats[1].Category)
+	assert.Equal(t, 2, stats[1].Count)
+	assert.Equal(t, 1, stats[1].Requests)
+}
+
+func TestDecrementStats(t *testing.T) {
+	repo := setupTestDB(t)
+
+	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
+	err := repo.IncrementStats("C123", date, map[string]CategoryStats{
+		"CI/CD":     {Requests: 2, Reactions: 2},
+		"Infra Bug": {Requests: 1, Reactions: 1},
+	})
+	assert.NoError(t, err)
+
+	// Decrement below zero and a category that was never stored
+	err = repo.DecrementStats("C123", date, map[string]CategoryStats{
+		"CI/CD":     {Requests: 1, Reactions: 1},
+		"Infra Bug": {Requests: 2, Reactions: 3},
+		"Capacity":  {Requests: 1, Reactions: 1},
+	})
+	assert.NoError(t, err, "Failed to decrement stats")
+
+	stats, err := repo.GetAggregatedStats("C123", date, date)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, "CI/CD", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Count)
+	assert.Equal(t, 1, stats[0].Requests)
+	assert.Equal(t, "Capacity", stats[1].Category)
+	assert.Equal(t, 0, stats[1].Count)
+	assert.Equal(t, 0, stats[1].Requests)
+	assert.Equal(t, "Infra Bug", stats[2].Category)
+	assert.Equal(t, 0, stats[2].Count)
+	assert.Equal(t, 0, stats[2].Requests)
+}
+
+func TestReplaceStats(t *testing.T) {
+	repo := setupTestDB(t)
+
+	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
+	repo.SaveStats("C123", date, map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 3}, "Infra Bug": {Requests: 1, Reactions: 7}})
+	repo.SaveStats("C123", date.AddDate(0, 0, 1), map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 1}})
+
+	err := repo.ReplaceStats("C123", date, map[string]CategoryStats{"Infra Bug": {Requests: 1, Reactions: 2}})
+	assert.NoError(t, err, "Failed to replace stats")
+
+	// The replaced day only keeps the new counts
+	stats, err := repo.GetAggregatedStats("C123", date, date)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "Infra Bug", stats[0].Category)
+	assert.Equal(t, 2, stats[0].Count)
+	assert.Equal(t, 1, stats[0].Requests)
+
+	// Other days are left alone
+	stats, err = repo.GetAggregatedStats("C123", date.AddDate(0, 0, 1), date.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, "CI/CD", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Count)
+}
+
+func TestGetRequestAndReactionCounts(t *testing.T) {
+	repo := setupTestDB(t)
+
+	start := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
+	repo.IncrementStats("C123", start, map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 5}})
+	repo.IncrementStats("C123", start.AddDate(0, 0, 1), map[string]CategoryStats{
+		"CI/CD":     {Requests: 2, Reactions: 2},
+		"Infra Bug": {Requests: 1, Reactions: 1},
+	})
+	repo.IncrementStats("C123", start.AddDate(0, 0, 2), map[string]CategoryStats{"CI/CD": {Requests: 1, Reactions: 1}})
+
+	requests, err := repo.GetRequestCounts("C123", start, start.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"CI/CD": 3, "Infra Bug": 1}, requests)
+
+	reactions, err := repo.GetReactionCounts("C123", start, start.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"CI/CD": 7, "Infra Bug": 1}, reactions)
+
+	requests, err = repo.GetRequestCounts("C999", start, start.AddDate(0, 0, 2))
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestGetRolledUpStats(t *testing.T) {
+	repo := setupTestDB(t)
+
+	date := time.Date(2025, 01, 29, 0, 0, 0, 0, time.UTC)
+	repo.IncrementStats("C123", date, map[string]CategoryStats{
+		"Infra bug": {Requests: 2, Reactions: 3},
+		"Capacity":  {Requests: 1, Reactions: 1},
+		"CI/CD":     {Requests: 1, Reactions: 4},
+	})
+	postedAt := time.Date(2025, 01, 29, 12, 0, 0, 0, time.Local)
+	for i, categories := range [][]string{{"Infra bug", "Capacity"}, {"Infra bug"}, {"CI/CD"}, {"Capacity"}} {
+		request := &Request{Channel: "C123", MessageTS: fmt.Sprintf("1738144800.00010%d", i), PostedAt: postedAt}
+		if i == 3 {
+			request.Author = "U0" // doesn't count
+		}
+		for _, category := range categories {
+			request.Categories = append(request.Categories, RequestCategory{Category: category})
+		}
+		assert.NoError(t, repo.SaveRequest(request))
+	}
+	tree := CategoryTree{"Infra bug": "Infra", "Capacity": "Infra"}
+	counted := func(request *Request) bool { return request.Author != "U0" }
+
+	// Top level, the request in both infra categories counts once
+	stats, err := repo.GetRolledUpStats(RollUpQuery{Channel: "C123", Start: date, End: date, Tree: tree, Filter: counted})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "CI/CD", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Requests)
+	assert.Equal(t, "Infra", stats[1].Category)
+	assert.Equal(t, 2, stats[1].Requests)
+	assert.Equal(t, 4, stats[1].Count)
+
+	// Drill down into a parent
+	stats, err = repo.GetRolledUpStats(RollUpQuery{Channel: "C123", Start: date, End: date, Tree: tree, Level: 1, Parent: "Infra", Filter: counted})
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "Capacity", stats[0].Category)
+	assert.Equal(t, 1, stats[0].Requests)
+	assert.Equal(t, "Infra bug", stats[1].Category)
+	assert.Equal(t, 2, stats[1].Requests)
+}
+
+func TestSaveRequest(t *testing.T) {
+	repo := setupTestDB(t)
+
+	postedAt := time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC)
+	request := &Request{
+		Channel:    "C123",
+		MessageTS:  "1738144800.000100",
+		Author:     "U123",
+		PostedAt:   postedAt,
+		Categories: []RequestCategory{{Category: "CI/CD", Count: 1}},
+	}
+	err := repo.SaveRequest(request)
+	assert.NoError(t, err, "Failed to save request")
+
+	// Saving the same message again updates the existing row
+	request = &Request{
+		Channel:    "C123",
+		MessageTS:  "1738144800.000100",
+		Author:     "U123",
+		Permalink:  "https://example.slack.com/archives/C123/p1738144800000100",
+		Text:       "jenkins is down",
+		PostedAt:   postedAt,
+		Reactions:  map[string]int{"eyes": 1, "bug": 2},
+		Categories: []RequestCategory{{Category: "Infra Bug", Count: 2, Source: CategorySourceReaction}},
+	}
+	err = repo.SaveRequest(request)
+	assert.NoError(t, err, "Failed to update request")
+
+	stored, err := repo.GetRequest("C123", "1738144800.000100")
+	assert.NoError(t, err)
+	assert.Equal(t, request.ID, stored.ID)
+	assert.Equal(t, request.Permalink, stored.Permalink)
+	assert.Equal(t, request.Text, stored.Text)
+	assert.Equal(t, map[string]int{"eyes": 1, "bug": 2}, stored.Reactions)
+	assert.Equal(t, []string{"Infra Bug"}, stored.CategoryNames())
+	assert.Equal(t, 2, stored.Categories[0].Count)
+	assert.Equal(t, CategorySourceReaction, stored.Categories[0].Source)
+
+	_, err = repo.GetRequest("C123", "unknown")
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+}
+
+func TestListRequests(t *testing.T) {
+	repo := setupTestDB(t)
+
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1", PostedAt: time.Date(2025, 01, 28, 10, 0, 0, 0, time.UTC),
+		Categories: []RequestCategory{{Category: "CI/CD", Count: 1}}})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "2", PostedAt: time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC),
+		Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}}})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "3", PostedAt: time.Date(2025, 01, 30, 10, 0, 0, 0, time.UTC),
+		Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}, {Category: "CI/CD", Count: 1}}})
+	repo.SaveRequest(&Request{Channel: "C999", MessageTS: "4", PostedAt: time.Date(2025, 01, 30, 10, 0, 0, 0, time.UTC),
+		Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}}})
+
+	// Filter by channel and category
+	requests, err := repo.ListRequests(RequestQuery{Channel: "C123", Category: "Infra Bug"})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "2", requests[0].MessageTS)
+	assert.Equal(t, "3", requests[1].MessageTS)
+	assert.Len(t, requests[1].Categories, 2)
+
+	// Filter by interval
+	requests, err = repo.ListRequests(RequestQuery{
+		Channel: "C123",
+		Start:   time.Date(2025, 01, 28, 0, 0, 0, 0, time.UTC),
+		End:     time.Date(2025, 01, 29, 23, 59, 59, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "1", requests[0].MessageTS)
+
+	// Filter with a function
+	requests, err = repo.ListRequests(RequestQuery{
+		Channel: "C123",
+		Filter:  func(request *Request) bool { return len(request.Categories) > 1 },
+	})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "3", requests[0].MessageTS)
+}
+
+func TestSyncCheckpoint(t *testing.T) {
+	repo := setupTestDB(t)
+
+	checkpoint, err := repo.GetSyncCheckpoint("C123")
+	assert.NoError(t, err)
+	assert.Empty(t, checkpoint, "Channel should have no checkpoint before the first sync")
+
+	assert.NoError(t, repo.SaveSyncCheckpoint("C123", "1738144800.000100"))
+	assert.NoError(t, repo.SaveSyncCheckpoint("C123", "1738148400.000200"))
+
+	checkpoint, err = repo.GetSyncCheckpoint("C123")
+	assert.NoError(t, err)
+	assert.Equal(t, "1738148400.000200", checkpoint)
+}
+
+func TestSyncFailures(t *testing.T) {
+	repo := setupTestDB(t)
+
+	assert.NoError(t, repo.RecordSyncFailure("C123", "1738148400.000200", "timeout"))
+	assert.NoError(t, repo.RecordSyncFailure("C123", "1738144800.000100", "timeout"))
+	assert.NoError(t, repo.RecordSyncFailure("C123", "1738144800.000100", "rate limited"))
+	assert.NoError(t, repo.RecordSyncFailure("C456", "1738144800.000100", "timeout"))
+
+	failures, err := repo.ListSyncFailures("C123", 3)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "1738144800.000100", failures[0].MessageTS, "Oldest failures should come first")
+	assert.Equal(t, 2, failures[0].Attempts)
+	assert.Equal(t, "rate limited", failures[0].Error)
+
+	failures, err = repo.ListSyncFailures("C123", 2)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1, "Failures out of attempts should be skipped")
+
+	assert.NoError(t, repo.DeleteSyncFailure("C123", "1738148400.000200"))
+	failures, err = repo.ListSyncFailures("C123", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+}
+
+func TestGetLifecycleStats(t *testing.T) {
+	repo := setupTestDB(t)
+
+	postedAt := time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := postedAt.Add(d)
+		return &t
+	}
+	requests := []*Request{
+		{MessageTS: "1.1", CategorizedAt: at(2 * time.Minute), AckedAt: at(5 * time.Minute), ResolvedAt: at(time.Hour),
+			Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}}},
+		{MessageTS: "1.2", AckedAt: at(15 * time.Minute),
+			Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}}},
+		{MessageTS: "1.3", AckedAt: at(30 * time.Minute), ResolvedAt: at(3 * time.Hour),
+			Categories: []RequestCategory{{Category: "Infra Bug", Count: 1}, {Category: "CI/CD", Count: 1}}},
+		{MessageTS: "1.4", Categories: []RequestCategory{{Category: "CI/CD", Count: 1}}},
+	}
+	for _, request := range requests {
+		request.Channel = "C123"
+		request.PostedAt = postedAt
+		assert.NoError(t, repo.SaveRequest(request))
+	}
+
+	stats, err := repo.GetLifecycleStats(RequestQuery{Channel: "C123"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+
+	assert.Equal(t, "CI/CD", stats[0].Category)
+	assert.Equal(t, 2, stats[0].Requests)
+	assert.Equal(t, 1, stats[0].Acked)
+	assert.Equal(t, 30*time.Minute, stats[0].MedianResponse)
+
+	assert.Equal(t, "Infra Bug", stats[1].Category)
+	assert.Equal(t, 3, stats[1].Requests)
+	assert.Equal(t, 1, stats[1].Categorized)
+	assert.Equal(t, 2*time.Minute, stats[1].MedianCategorization)
+	assert.Equal(t, 3, stats[1].Acked)
+	assert.Equal(t, 2, stats[1].Resolved)
+	assert.Equal(t, 15*time.Minute, stats[1].MedianResponse)
+	assert.Equal(t, 30*time.Minute, stats[1].P90Response)
+	assert.Equal(t, time.Hour, stats[1].MedianResolution)
+	assert.Equal(t, 3*time.Hour, stats[1].P90Resolution)
+}
+
+func TestListRequests_Open(t *testing.T) {
+	repo := setupTestDB(t)
+
+	postedAt := time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC)
+	ackedAt := postedAt.Add(time.Minute)
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.1", PostedAt: postedAt})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.2", PostedAt: postedAt, BeaconAt: &postedAt, AckedAt: &ackedAt})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.3", PostedAt: postedAt, AckedAt: &ackedAt, ResolvedAt: &ackedAt})
+
+	requests, err := repo.ListRequests(RequestQuery{Channel: "C123", NotAcked: true})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "1.1", requests[0].MessageTS)
+
+	requests, err = repo.ListRequests(RequestQuery{Channel: "C123", NotResolved: true})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	requests, err = repo.ListRequests(RequestQuery{Channel: "C123", Beaconed: true})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "1.2", requests[0].MessageTS)
+}
+
+func TestGetWorkload(t *testing.T) {
+	repo := setupTestDB(t)
+
+	postedAt := time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC)
+	resolvedAt := postedAt.Add(time.Hour)
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.1", PostedAt: postedAt, Assignee: "U2"})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.2", PostedAt: postedAt, Assignee: "U1", ResolvedAt: &resolvedAt})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.3", PostedAt: postedAt, Assignee: "U1"})
+	repo.SaveRequest(&Request{Channel: "C123", MessageTS: "1.4", PostedAt: postedAt})
+	repo.SaveRequest(&Request{Channel: "C456", MessageTS: "1.5", PostedAt: postedAt, Assignee: "U2"})
+
+	workload, err := repo.GetWorkload(RequestQuery{Channel: "C123"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Workload{
+		{Assignee: "U1", Requests: 2, Resolved: 1},
+		{Assignee: "U2", Requests: 1},
+	}, workload, "Unassigned requests should be skipped")
+}
+
+func TestRecordSLABreach(t *testing.T) {
+	repo := setupTestDB(t)
+
+	dueAt := time.Date(2025, 01, 29, 10, 30, 0, 0, time.UTC)
+	breach := &SLABreach{RequestID: 1, Kind: SLAKindAck, Channel: "C123", Category: "Infra Bug",
+		Target: 30 * time.Minute, DueAt: dueAt, DetectedAt: dueAt.Add(time.Minute)}
+	created, err := repo.RecordSLABreach(breach)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// The same request breaches the same target only once
+	created, err = repo.RecordSLABreach(&SLABreach{RequestID: 1, Kind: SLAKindAck, Channel: "C123",
+		Category: "Infra Bug", Target: 30 * time.Minute, DueAt: dueAt, DetectedAt: dueAt.Add(2 * time.Minute)})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	created, err = repo.RecordSLABreach(&SLABreach{RequestID: 1, Kind: SLAKindResolve, Channel: "C123",
+		Category: "Infra Bug", Target: 4 * time.Hour, DueAt: dueAt.Add(4 * time.Hour), DetectedAt: dueAt.Add(4 * time.Hour)})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	breaches, err := repo.ListSLABreaches(SLABreachQuery{Channel: "C123", End: dueAt})
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 1)
+	assert.Equal(t, SLAKindAck, breaches[0].Kind)
+	assert.Equal(t, 30*time.Minute, breaches[0].Target)
+	assert.False(t, breaches[0].Notified)
+
+	assert.NoError(t, repo.MarkSLABreachNotified(breach.ID))
+	breaches, err = repo.ListSLABreaches(SLABreachQuery{Channel: "C123", End: dueAt})
+	assert.NoError(t, err)
+	assert.True(t, breaches[0].Notified)
+
+	breaches, err = repo.ListSLABreaches(SLABreachQuery{Category: "Infra Bug"})
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 2)
+
+	breaches, err = repo.ListSLABreaches(SLABreachQuery{Channel: "C123", NotNotified: true})
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 1)
+	assert.Equal(t, SLAKindResolve, breaches[0].Kind)
+}
+
+func TestSaveRequest_ManualCategory(t *testing.T) {
+	repo := setupTestDB(t)
+
+	err := repo.SaveRequest(&Request{
+		Channel:        "C123",
+		MessageTS:      "1738144800.000100",
+		PostedAt:       time.Date(2025, 01, 29, 10, 0, 0, 0, time.UTC),
+		ManualCategory: "CI/CD",
+		CategorizedBy:  "U123",
+		CategoryNote:   "jenkins again",
+		TicketKey:      "OPS-42",
+		Categories:     []RequestCategory{{Category: "CI/CD", Source: CategorySourceManual}},
+	})
+	assert.NoError(t, err)
+
+	stored, err := repo.GetRequest("C123", "1738144800.000100")
+	assert.NoError(t, err)
+	assert.Equal(t, "CI/CD", stored.ManualCategory)
+	assert.Equal(t, "U123", stored.CategorizedBy)
+	assert.Equal(t, "jenkins again", stored.CategoryNote)
+	assert.Equal(t, "OPS-42", stored.TicketKey)
+	assert.Equal(t, CategorySourceManual, stored.Categories[0].Source)
+}
+
+func TestRecordWebhookFailure(t *testing.T) {
+	repo := setupTestDB(t)
+
+	for _, event := range []string{"request.categorized", "sla.breached"} {
+		err := repo.RecordWebhookFailure(&WebhookFailure{
+			URL:        "https://hooks.example.com/tars",
+			Event:      event,
+			DeliveryID: "delivery-" + event,
+			Payload:    `{"event":"` + event + `"}`,
+			Attempts:   5,
+			Error:      "unexpected status 503",
+		})
+		assert.NoError(t, err)
+	}
+
+	failures, err := repo.ListWebhookFailures(1)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "sla.breached", failures[0].Event)
+	assert.Equal(t, 5, failures[0].Attempts)
+
+	failures, err = repo.ListWebhookFailures(0)
+	assert.NoError(t, err)
+	assert.Len(t, failures, 2)
+}
@@ -0,0 +1,41 @@
+package storage
+
+import "sort"
+
+// Workload is how many requests a person was assigned.
+type Workload struct {
+	Assignee string
+	Requests int // requests assigned
+	Resolved int // assigned requests that are resolved
+}
+
+// workload counts the requests per assignee, most requests first.
+func workload(requests []Request) []Workload {
+	byAssignee := make(map[string]*Workload)
+	for _, request := range requests {
+		if request.Assignee == "" {
+			continue
+		}
+		w := byAssignee[request.Assignee]
+		if w == nil {
+			w = &Workload{Assignee: request.Assignee}
+			byAssignee[request.Assignee] = w
+		}
+		w.Requests++
+		if request.ResolvedAt != nil {
+			w.Resolved++
+		}
+	}
+
+	results := make([]Workload, 0, len(byAssignee))
+	for _, w := range byAssignee {
+		results = append(results, *w)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Requests != results[j].Requests {
+			return results[i].Requests > results[j].Requests
+		}
+		return results[i].Assignee < results[j].Assignee
+	})
+	return results
+}
@@ -0,0 +1,101 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// DefaultJiraIssueType is the type of the created Jira issues unless configured.
+const DefaultJiraIssueType = "Task"
+
+// labelPattern matches what Jira labels can't contain.
+var labelPattern = regexp.MustCompile(`\s+`)
+
+// Jira creates issues with the Jira REST API.
+type Jira struct {
+	baseURL   string
+	username  string
+	token     string
+	issueType string
+	client    *http.Client
+}
+
+// NewJira creates a Jira tracker for the site at baseURL, e.g.
+// "https://example.atlassian.net", authenticating with the account email and
+// an API token. A nil client uses a default one.
+func NewJira(baseURL, username, token, issueType string, client *http.Client) *Jira {
+	if issueType == "" {
+		issueType = DefaultJiraIssueType
+	}
+	return &Jira{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		username:  username,
+		token:     token,
+		issueType: issueType,
+		client:    defaultClient(client),
+	}
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+type jiraFields struct {
+	Project     jiraKey  `json:"project"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	IssueType   jiraName `json:"issuetype"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// CreateIssue creates an issue in the project, labeled with the category.
+func (j *Jira) CreateIssue(ctx context.Context, issue Issue) (*Ticket, error) {
+	fields := jiraFields{
+		Project:     jiraKey{Key: issue.Project},
+		Summary:     issue.Summary,
+		Description: issue.Description,
+		IssueType:   jiraName{Name: j.issueType},
+	}
+	if issue.Category != "" {
+		fields.Labels = []string{labelPattern.ReplaceAllString(issue.Category, "-")}
+	}
+	body, err := json.Marshal(map[string]jiraFields{"fields": fields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode jira issue: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.baseURL+"/rest/api/2/issue", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build jira request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(j.username, j.token)
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jira issue: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("failed to create jira issue: %w", err)
+	}
+
+	var created jiraKey
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	if created.Key == "" {
+		return nil, fmt.Errorf("jira response has no issue key")
+	}
+	return &Ticket{Key: created.Key, URL: j.baseURL + "/browse/" + created.Key}, nil
+}
@@ -0,0 +1,59 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJiraCreateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/rest/api/2/issue", r.URL.Path)
+		username, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "bot@example.com", username)
+		assert.Equal(t, "secret", token)
+
+		var body struct {
+			Fields jiraFields `json:"fields"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "OPS", body.Fields.Project.Key)
+		assert.Equal(t, "jenkins is down", body.Fields.Summary)
+		assert.Equal(t, "Task", body.Fields.IssueType.Name)
+		assert.Equal(t, []string{"Infra-bug"}, body.Fields.Labels)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"10000","key":"OPS-42","self":"https://example.atlassian.net/rest/api/2/issue/10000"}`))
+	}))
+	defer server.Close()
+
+	jira := NewJira(server.URL+"/", "bot@example.com", "secret", "", nil)
+	ticket, err := jira.CreateIssue(context.Background(), Issue{
+		Project:     "OPS",
+		Summary:     "jenkins is down",
+		Description: "From Slack",
+		Category:    "Infra bug",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS-42", ticket.Key)
+	assert.Equal(t, server.URL+"/browse/OPS-42", ticket.URL)
+}
+
+func TestJiraCreateIssue_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":{"project":"valid project is required"}}`))
+	}))
+	defer server.Close()
+
+	jira := NewJira(server.URL, "bot@example.com", "secret", "Bug", server.Client())
+	_, err := jira.CreateIssue(context.Background(), Issue{Project: "NOPE", Summary: "jenkins is down"})
+	assert.ErrorContains(t, err, "400")
+	assert.ErrorContains(t, err, "valid project is required")
+}
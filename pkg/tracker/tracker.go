@@ -0,0 +1,50 @@
+// Package tracker creates tickets in issue trackers.
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTimeout bounds the requests to the tracker when no HTTP client is given.
+const defaultTimeout = 30 * time.Second
+
+// Issue is a ticket to create.
+type Issue struct {
+	Project     string `json:"project"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+}
+
+// Ticket is a created ticket.
+type Ticket struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// IssueTracker creates tickets.
+type IssueTracker interface {
+	CreateIssue(ctx context.Context, issue Issue) (*Ticket, error)
+}
+
+func defaultClient(client *http.Client) *http.Client {
+	if client == nil {
+		return &http.Client{Timeout: defaultTimeout}
+	}
+	return client
+}
+
+// checkResponse returns an error with the start of the body for responses
+// that aren't successful.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,58 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Webhook creates tickets by posting the issue as JSON to a URL, for trackers
+// without a dedicated implementation. The response has to be a JSON ticket,
+// e.g. {"key": "OPS-42", "url": "https://tracker.example.com/OPS-42"}.
+type Webhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhook creates a webhook tracker posting to the URL with the extra
+// headers, e.g. for authentication. A nil client uses a default one.
+func NewWebhook(url string, headers map[string]string, client *http.Client) *Webhook {
+	return &Webhook{url: url, headers: headers, client: defaultClient(client)}
+}
+
+// CreateIssue posts the issue to the webhook.
+func (w *Webhook) CreateIssue(ctx context.Context, issue Issue) (*Ticket, error) {
+	body, err := json.Marshal(issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode issue: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call issue webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return nil, fmt.Errorf("failed to call issue webhook: %w", err)
+	}
+
+	var ticket Ticket
+	if err := json.NewDecoder(resp.Body).Decode(&ticket); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook response: %w", err)
+	}
+	if ticket.Key == "" {
+		return nil, fmt.Errorf("webhook response has no ticket key")
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,48 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookCreateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var issue Issue
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&issue))
+		assert.Equal(t, Issue{Project: "OPS", Summary: "jenkins is down", Category: "CI/CD"}, issue)
+
+		w.Write([]byte(`{"key":"OPS-7","url":"https://tracker.example.com/OPS-7"}`))
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, map[string]string{"Authorization": "Bearer secret"}, nil)
+	ticket, err := webhook.CreateIssue(context.Background(), Issue{Project: "OPS", Summary: "jenkins is down", Category: "CI/CD"})
+	assert.NoError(t, err)
+	assert.Equal(t, &Ticket{Key: "OPS-7", URL: "https://tracker.example.com/OPS-7"}, ticket)
+}
+
+func TestWebhookCreateIssue_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewWebhook(server.URL+"/down", nil, nil).CreateIssue(context.Background(), Issue{Summary: "jenkins is down"})
+	assert.ErrorContains(t, err, "503")
+
+	_, err = NewWebhook(server.URL, nil, nil).CreateIssue(context.Background(), Issue{Summary: "jenkins is down"})
+	assert.ErrorContains(t, err, "no ticket key")
+}
@@ -1,6 +1,11 @@
 package utils
 
 import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -11,27 +16,139 @@ type Config struct {
 		SigningSecret string `mapstructure:"signing_secret"`
 	} `mapstructure:"slack"`
 	Bot struct {
-		LogLevel string `mapstructure:"log_level"`
+		LogLevel     string        `mapstructure:"log_level"`
+		SyncInterval time.Duration `mapstructure:"sync_interval"` // how often channel history is synced, 0 disables syncing
+		SyncLookback time.Duration `mapstructure:"sync_lookback"` // how much history the first sync of a channel pulls
+		Workers      int           `mapstructure:"workers"`       // how many messages are processed in parallel
+
+		UncategorizedCategory string `mapstructure:"uncategorized_category"` // category of counted requests no rule matched, empty to drop them
 	} `mapstructure:"bot"`
 	Database struct {
 		Driver string `mapstructure:"driver"`
 		DSN    string `mapstructure:"dsn"`
 	} `mapstructure:"db"`
-	Channels      []ChannelConfig              `mapstructure:"channels"`
-	ReactionCache map[string]map[string]string // channelID -> reaction -> category
+	BusinessHours     BusinessHoursConfig             `mapstructure:"business_hours"`
+	IssueTracker      IssueTrackerConfig              `mapstructure:"issue_tracker"`
+	Webhooks          []WebhookConfig                 `mapstructure:"webhooks"`      // where request and SLA events are posted
+	DefaultRules      []RuleConfig                    `mapstructure:"default_rules"` // rules every channel inherits
+	RuleSets          map[string][]RuleConfig         `mapstructure:"rule_sets"`     // named rules channels can opt into
+	Channels          []ChannelConfig                 `mapstructure:"channels"`
+	RulesCache        map[string][]RuleConfig         // channelID -> effective rules
+	ReactionCache     map[string]map[string]string    // channelID -> reaction -> category
+	ReactionRuleCache map[string]map[string]RuleMatch // channelID -> reaction -> matching rule
+	TextRuleCache     map[string][]TextRule           // channelID -> compiled text rules
+	CategoryTreeCache map[string]map[string]string    // channelID -> category -> parent category
+	EmojiAliases      *EmojiAliases                   // workspace emoji aliases, nil until loaded
 
+	cacheMu sync.RWMutex // guards the caches, which are rebuilt when the emoji aliases change
 }
 
 type ChannelConfig struct {
-	Name           string       `mapstructure:"name"`
-	Rules          []RuleConfig `mapstructure:"rules"`
-	BeaconReaction string       `mapstructure:"beacon_reaction"`
-	ID             string       `mapstructure:"id"`
+	Name               string         `mapstructure:"name"`
+	Rules              []RuleConfig   `mapstructure:"rules"`
+	BeaconReaction     string         `mapstructure:"beacon_reaction"`
+	ID                 string         `mapstructure:"id"`
+	CountThreadReplies bool           `mapstructure:"count_thread_replies"` // count categorized reactions on thread replies toward the parent request
+	BeaconMode         string         `mapstructure:"beacon_mode"`          // one of the BeaconMode* values
+	CategoryPolicy     string         `mapstructure:"category_policy"`      // one of the CategoryPolicy* values
+	RuleSets           []string       `mapstructure:"rule_sets"`            // names of the rule sets the channel inherits
+	SkipDefaultRules   bool           `mapstructure:"skip_default_rules"`   // don't inherit the default rules
+	AckReaction        string         `mapstructure:"ack_reaction"`         // reaction of the first responder, e.g. ":eyes:"
+	ResolvedReaction   string         `mapstructure:"resolved_reaction"`    // reaction marking the request resolved, e.g. ":white_check_mark:"
+	SLA                SLAConfig      `mapstructure:"sla"`
+	OnCall             OnCallConfig   `mapstructure:"on_call"`
+	Reminders          ReminderConfig `mapstructure:"reminders"`
+	CategorizeButtons  bool           `mapstructure:"categorize_buttons"` // answer new messages with a button per category
+	IssueProject       string         `mapstructure:"issue_project"`      // tracker project of tickets from the channel, overrides the tracker default
 }
 
+// Beacon modes decide which messages of a channel are counted as requests.
+const (
+	BeaconModeRequired    = "required"       // only messages with the beacon reaction
+	BeaconModeAnyReaction = "any_reaction"   // messages with the beacon or any other reaction
+	BeaconModeText        = "beacon_or_text" // messages with the beacon or text a text rule matches
+	BeaconModeDisabled    = "disabled"       // every message
+)
+
+// Category policies decide which of the matched categories a request lands in.
+const (
+	CategoryPolicyAll              = "all"                 // every matched category
+	CategoryPolicyFirstByRuleOrder = "first_by_rule_order" // the category of the first matching rule
+	CategoryPolicyHighestPriority  = "highest_priority"    // the category of the matching rule with the highest priority
+	CategoryPolicyMajorityVote     = "majority_vote"       // the category with the most reactions
+)
+
+// BusinessHoursConfig defines when the team is at work. Response times and
+// SLA targets count business time only when it's set.
+type BusinessHoursConfig struct {
+	Timezone     string            `mapstructure:"timezone"`      // e.g. "Europe/Berlin", UTC if empty
+	Hours        map[string]string `mapstructure:"hours"`         // weekday -> open hours, e.g. "monday": "09:00-17:00"
+	HolidaysFile string            `mapstructure:"holidays_file"` // file with one "2006-01-02" date per line
+}
+
+// Issue tracker types.
+const (
+	IssueTrackerJira    = "jira"    // the Jira REST API
+	IssueTrackerWebhook = "webhook" // a URL that takes the issue as JSON
+)
+
+// IssueTrackerConfig defines where tickets are created from requests.
+type IssueTrackerConfig struct {
+	Type      string            `mapstructure:"type"`       // one of the IssueTracker* values, empty disables tickets
+	URL       string            `mapstructure:"url"`        // Jira site or webhook URL
+	Username  string            `mapstructure:"username"`   // Jira account email
+	Token     string            `mapstructure:"token"`      // Jira API token
+	IssueType string            `mapstructure:"issue_type"` // Jira issue type, "Task" if empty
+	Project   string            `mapstructure:"project"`    // default project of the tickets
+	Headers   map[string]string `mapstructure:"headers"`    // extra webhook request headers
+}
+
+// WebhookConfig defines an endpoint TARS posts events to.
+type WebhookConfig struct {
+	URL    string   `mapstructure:"url"`
+	Events []string `mapstructure:"events"` // events to post, all of them if empty
+	Secret string   `mapstructure:"secret"` // HMAC key of the X-Tars-Signature header, unsigned if empty
+}
+
+// SLAConfig defines how fast requests of a channel have to be handled.
+type SLAConfig struct {
+	EscalationChannel string      `mapstructure:"escalation_channel"` // where breaches are posted, the request thread if empty
+	Targets           []SLATarget `mapstructure:"targets"`
+}
+
+// SLATarget is the time requests of a category have to be acked or resolved in.
+type SLATarget struct {
+	Category      string        `mapstructure:"category"`       // applies to its child categories too, every category if empty
+	AckWithin     time.Duration `mapstructure:"ack_within"`     // 0 for no ack target
+	ResolveWithin time.Duration `mapstructure:"resolve_within"` // 0 for no resolution target
+}
+
+// OnCallConfig defines who is assigned new requests of a channel, either from
+// a rotation or from an iCalendar file.
+type OnCallConfig struct {
+	Users       []string      `mapstructure:"users"`        // Slack user IDs taking turns, in order
+	Start       string        `mapstructure:"start"`        // RFC 3339 time the first user's shift starts
+	ShiftLength time.Duration `mapstructure:"shift_length"` // how long a shift lasts, a week if 0
+	ICSFile     string        `mapstructure:"ics_file"`     // calendar with one event per shift, used instead of users
+}
+
+// ReminderConfig defines when open requests of a channel are brought up again.
+type ReminderConfig struct {
+	IdleAfter    time.Duration `mapstructure:"idle_after"`    // time without activity before a reminder, 0 disables reminders
+	MaxReminders int           `mapstructure:"max_reminders"` // reminders per request, DefaultMaxReminders if 0
+	DMAssignee   bool          `mapstructure:"dm_assignee"`   // also send the assignee a direct message
+}
+
+// DefaultMaxReminders is how many reminders a request gets unless configured.
+const DefaultMaxReminders = 3
+
 type RuleConfig struct {
-	Reaction string `mapstructure:"reaction"`
-	Category string `mapstructure:"category"`
+	Reaction string   `mapstructure:"reaction"`
+	Keywords []string `mapstructure:"keywords"` // words matched in the message text, case-insensitive
+	Pattern  string   `mapstructure:"pattern"`  // regular expression matched against the message text, case-insensitive
+	Category string   `mapstructure:"category"`
+	Parent   string   `mapstructure:"parent"`   // category the rule category belongs to, e.g. "Infra" for "Infra bug"
+	Priority int      `mapstructure:"priority"` // matches with a higher priority win, see CategoryPolicy
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -46,6 +163,12 @@ func LoadConfig(path string) (*Config, error) {
 	_ = viper.BindEnv("slack.app_token", "SLACK_APP_TOKEN")
 	_ = viper.BindEnv("slack.bot_token", "SLACK_BOT_TOKEN")
 	_ = viper.BindEnv("slack.signing_secret", "SLACK_SIGNING_SECRET")
+	_ = viper.BindEnv("issue_tracker.token", "ISSUE_TRACKER_TOKEN")
+
+	viper.SetDefault("bot.sync_interval", "5m")
+	viper.SetDefault("bot.sync_lookback", "24h")
+	viper.SetDefault("bot.workers", 4)
+	viper.SetDefault("bot.uncategorized_category", "Uncategorized")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
@@ -59,14 +182,138 @@ func LoadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
-func (config *Config) BuildReactionCache() {
-	config.ReactionCache = make(map[string]map[string]string)
+// BuildReactionCache prepares the channel rules for matching: reaction rules
+// are indexed by their resolved reaction name, the first of several rules for
+// the same reaction wins, text rules are compiled into regular expressions.
+func (config *Config) BuildReactionCache() error {
+	reactionCache := make(map[string]map[string]string)
+	reactionRuleCache := make(map[string]map[string]RuleMatch)
+	textRuleCache := make(map[string][]TextRule)
+	categoryTreeCache := make(map[string]map[string]string)
+	rulesCache := make(map[string][]RuleConfig)
 
 	for _, channel := range config.Channels {
+		if err := validateBeaconMode(channel); err != nil {
+			return err
+		}
+		if err := validateCategoryPolicy(channel); err != nil {
+			return err
+		}
+		if err := validateSLA(channel); err != nil {
+			return err
+		}
+		rules, err := config.effectiveRules(channel)
+		if err != nil {
+			return err
+		}
+		rulesCache[channel.ID] = rules
+
 		reactionMap := make(map[string]string)
-		for _, rule := range channel.Rules {
-			reactionMap[rule.Reaction] = rule.Category
+		reactionRules := make(map[string]RuleMatch)
+		parents := make(map[string]string)
+		var textRules []TextRule
+		for order, rule := range rules {
+			if rule.Parent != "" {
+				if parent, exists := parents[rule.Category]; exists && parent != rule.Parent {
+					return fmt.Errorf("category %q in channel %s has conflicting parents %q and %q", rule.Category, channel.ID, parent, rule.Parent)
+				}
+				parents[rule.Category] = rule.Parent
+			}
+			reaction := ResolveReactionName(config, rule.Reaction)
+			// rules come in precedence order, so the first rule for a reaction wins
+			if _, exists := reactionMap[reaction]; reaction != "" && !exists {
+				reactionMap[reaction] = rule.Category
+				reactionRules[reaction] = RuleMatch{Category: rule.Category, Priority: rule.Priority, Order: order}
+			}
+			textRule, ok, err := compileTextRule(rule, order)
+			if err != nil {
+				return fmt.Errorf("invalid rule for category %q in channel %s: %w", rule.Category, channel.ID, err)
+			}
+			if ok {
+				textRules = append(textRules, textRule)
+			}
+		}
+		reactionCache[channel.ID] = reactionMap
+		reactionRuleCache[channel.ID] = reactionRules
+		textRuleCache[channel.ID] = textRules
+
+		if err := checkCategoryTree(parents); err != nil {
+			return fmt.Errorf("invalid categories in channel %s: %w", channel.ID, err)
+		}
+		categoryTreeCache[channel.ID] = parents
+	}
+
+	// the caches are swapped at once, so lookups never see a half built cache
+	config.cacheMu.Lock()
+	defer config.cacheMu.Unlock()
+	config.ReactionCache = reactionCache
+	config.ReactionRuleCache = reactionRuleCache
+	config.TextRuleCache = textRuleCache
+	config.CategoryTreeCache = categoryTreeCache
+	config.RulesCache = rulesCache
+	return nil
+}
+
+// effectiveRules merges the rules the channel inherits with its own. Channel
+// rules come first, then the rule sets in the listed order, then the default
+// rules. An inherited rule is dropped when a rule before it has the same
+// identity, so channels override inherited rules by redefining them.
+func (config *Config) effectiveRules(channel ChannelConfig) ([]RuleConfig, error) {
+	layers := [][]RuleConfig{channel.Rules}
+	for _, name := range channel.RuleSets {
+		set, exists := config.RuleSets[name]
+		if !exists {
+			// viper lowercases map keys
+			set, exists = config.RuleSets[strings.ToLower(name)]
+		}
+		if !exists {
+			return nil, fmt.Errorf("unknown rule set %q in channel %s", name, channel.ID)
+		}
+		layers = append(layers, set)
+	}
+	if !channel.SkipDefaultRules {
+		layers = append(layers, config.DefaultRules)
+	}
+
+	var rules []RuleConfig
+	defined := make(map[string]bool)
+	for i, layer := range layers {
+		// rules within a layer never override each other
+		var added []string
+		for _, rule := range layer {
+			key := ruleKey(config, rule)
+			if i > 0 && defined[key] {
+				continue
+			}
+			rules = append(rules, rule)
+			added = append(added, key)
+		}
+		for _, key := range added {
+			defined[key] = true
+		}
+	}
+	return rules, nil
+}
+
+// ruleKey identifies what a rule matches on: reaction rules by their
+// reaction, text rules by their category.
+func ruleKey(config *Config, rule RuleConfig) string {
+	if reaction := ResolveReactionName(config, rule.Reaction); reaction != "" {
+		return "reaction:" + reaction
+	}
+	return "text:" + rule.Category
+}
+
+// checkCategoryTree makes sure no category is its own ancestor.
+func checkCategoryTree(parents map[string]string) error {
+	for category := range parents {
+		seen := map[string]bool{category: true}
+		for parent, ok := parents[category]; ok; parent, ok = parents[parent] {
+			if seen[parent] {
+				return fmt.Errorf("category %q is its own ancestor", category)
+			}
+			seen[parent] = true
 		}
-		config.ReactionCache[channel.ID] = reactionMap
 	}
+	return nil
 }
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// skinTonePattern matches the skin tone modifier Slack appends to reaction
+// names, e.g. "+1::skin-tone-3".
+var skinTonePattern = regexp.MustCompile(`:+skin-tone-\d+$`)
+
+// standardAliases maps common alternative names of standard emoji to the
+// name Slack reports in reaction events.
+var standardAliases = map[string]string{
+	"thumbsup":   "+1",
+	"thumbsdown": "-1",
+	"hankey":     "poop",
+	"shit":       "poop",
+}
+
+// maxAliasDepth limits how many aliases are followed, in case they form a cycle.
+const maxAliasDepth = 5
+
+// NormalizeReactionName returns the reaction name the way Slack reports it,
+// e.g. ":eyes:" from the configuration becomes "eyes" and skin tone variants
+// like "+1::skin-tone-3" become "+1".
+func NormalizeReactionName(name string) string {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), ":"))
+	return strings.TrimRight(skinTonePattern.ReplaceAllString(name, ""), ":")
+}
+
+// ResolveReactionName normalizes the reaction name and resolves standard and
+// workspace emoji aliases, so every name of an emoji maps to the same one.
+func ResolveReactionName(config *Config, name string) string {
+	name = NormalizeReactionName(name)
+	if canonical, ok := standardAliases[name]; ok {
+		return canonical
+	}
+	if config == nil || config.EmojiAliases == nil {
+		return name
+	}
+	return config.EmojiAliases.Resolve(name)
+}
+
+// EmojiAliases is a local cache of the workspace emoji aliases from emoji.list.
+// It is safe for concurrent use.
+type EmojiAliases struct {
+	mu        sync.RWMutex
+	aliases   map[string]string // alias -> emoji
+	updatedAt time.Time
+}
+
+// NewEmojiAliases returns an empty alias cache.
+func NewEmojiAliases() *EmojiAliases {
+	return &EmojiAliases{aliases: make(map[string]string)}
+}
+
+// Update replaces the cached aliases with the ones from an emoji.list
+// response, where aliases have an "alias:<emoji>" value instead of an image URL.
+func (a *EmojiAliases) Update(emoji map[string]string) {
+	aliases := make(map[string]string)
+	for name, value := range emoji {
+		if target, ok := strings.CutPrefix(value, "alias:"); ok {
+			aliases[NormalizeReactionName(name)] = NormalizeReactionName(target)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.aliases = aliases
+	a.updatedAt = time.Now()
+}
+
+// Resolve returns the emoji the normalized name is an alias of, or the name itself.
+func (a *EmojiAliases) Resolve(name string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for i := 0; i < maxAliasDepth; i++ {
+		target, ok := a.aliases[name]
+		if !ok {
+			break
+		}
+		name = target
+	}
+	if canonical, ok := standardAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// UpdatedAt returns when the aliases were last updated, zero if never.
+func (a *EmojiAliases) UpdatedAt() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.updatedAt
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeReactionName(t *testing.T) {
+	assert.Equal(t, "eyes", NormalizeReactionName(":eyes:"))
+	assert.Equal(t, "eyes", NormalizeReactionName("eyes"))
+	assert.Equal(t, "white_check_mark", NormalizeReactionName(" :white_check_mark: "))
+	assert.Equal(t, "", NormalizeReactionName(""))
+	assert.Equal(t, "+1", NormalizeReactionName("+1::skin-tone-3"))
+	assert.Equal(t, "wave", NormalizeReactionName(":wave::skin-tone-6:"))
+	assert.Equal(t, "eyes", NormalizeReactionName("EYES"))
+}
+
+func TestEmojiAliases(t *testing.T) {
+	aliases := NewEmojiAliases()
+	assert.True(t, aliases.UpdatedAt().IsZero())
+
+	aliases.Update(map[string]string{
+		"party-parrot": "https://emoji.slack-edge.com/T123/party-parrot/abc.gif",
+		"parrot":       "alias:party-parrot",
+		"birb":         "alias:parrot",
+		"like":         "alias:thumbsup",
+		"loop-a":       "alias:loop-b",
+		"loop-b":       "alias:loop-a",
+	})
+	assert.False(t, aliases.UpdatedAt().IsZero())
+
+	assert.Equal(t, "party-parrot", aliases.Resolve("parrot"))
+	assert.Equal(t, "party-parrot", aliases.Resolve("birb"), "Alias chains should be followed")
+	assert.Equal(t, "+1", aliases.Resolve("like"), "Aliases of standard emoji should resolve to the reported name")
+	assert.Equal(t, "bug", aliases.Resolve("bug"))
+	assert.NotPanics(t, func() { aliases.Resolve("loop-a") }, "Alias cycles should not hang")
+}
+
+func TestResolveReactionName(t *testing.T) {
+	config := &Config{EmojiAliases: NewEmojiAliases()}
+	config.EmojiAliases.Update(map[string]string{"lgtm": "alias:white_check_mark"})
+
+	assert.Equal(t, "white_check_mark", ResolveReactionName(config, ":lgtm:"))
+	assert.Equal(t, "+1", ResolveReactionName(config, "thumbsup::skin-tone-2"))
+	assert.Equal(t, "eyes", ResolveReactionName(&Config{}, ":eyes:"), "Config without aliases should only normalize")
+}
@@ -1,18 +1,152 @@
 package utils
 
-import "log"
+import "fmt"
 
+// GetChannelConfig returns the configuration of the channel, if it is configured.
+func GetChannelConfig(config *Config, channelID string) (*ChannelConfig, bool) {
+	for i := range config.Channels {
+		if config.Channels[i].ID == channelID {
+			return &config.Channels[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetControllingReaction returns the resolved beacon reaction of the channel.
 func GetControllingReaction(config *Config, channelID string) string {
 	for _, channel := range config.Channels {
 		if channel.ID == channelID {
-			return channel.BeaconReaction
+			return ResolveReactionName(config, channel.BeaconReaction)
 		}
 	}
 	return ""
 }
 
+// GetAckReaction returns the resolved first responder reaction of the channel.
+func GetAckReaction(config *Config, channelID string) string {
+	channel, ok := GetChannelConfig(config, channelID)
+	if !ok {
+		return ""
+	}
+	return ResolveReactionName(config, channel.AckReaction)
+}
+
+// GetResolvedReaction returns the resolved reaction marking requests of the channel resolved.
+func GetResolvedReaction(config *Config, channelID string) string {
+	channel, ok := GetChannelConfig(config, channelID)
+	if !ok {
+		return ""
+	}
+	return ResolveReactionName(config, channel.ResolvedReaction)
+}
+
+// GetBeaconMode returns the beacon mode of the channel. Channels without a mode
+// require the beacon if they have one, and count every message otherwise.
+// Invalid modes are rejected by BuildReactionCache, here they fall back to
+// the default.
+func GetBeaconMode(config *Config, channelID string) string {
+	channel, ok := GetChannelConfig(config, channelID)
+	if !ok {
+		return BeaconModeDisabled
+	}
+
+	hasBeacon := NormalizeReactionName(channel.BeaconReaction) != ""
+	switch channel.BeaconMode {
+	case BeaconModeAnyReaction, BeaconModeText, BeaconModeDisabled:
+		return channel.BeaconMode
+	case BeaconModeRequired:
+		if !hasBeacon {
+			return BeaconModeDisabled
+		}
+	}
+
+	if hasBeacon {
+		return BeaconModeRequired
+	}
+	return BeaconModeDisabled
+}
+
+// validateBeaconMode checks the beacon mode of the channel.
+func validateBeaconMode(channel ChannelConfig) error {
+	switch channel.BeaconMode {
+	case "", BeaconModeAnyReaction, BeaconModeText, BeaconModeDisabled:
+		return nil
+	case BeaconModeRequired:
+		if NormalizeReactionName(channel.BeaconReaction) == "" {
+			return fmt.Errorf("channel %s requires a beacon but has no beacon_reaction", channel.ID)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown beacon mode %q for channel %s", channel.BeaconMode, channel.ID)
+	}
+}
+
+// GetCategoryPolicy returns the category policy of the channel, CategoryPolicyAll by default.
+// Unknown policies are rejected by BuildReactionCache, here they fall back to the default.
+func GetCategoryPolicy(config *Config, channelID string) string {
+	channel, ok := GetChannelConfig(config, channelID)
+	if !ok {
+		return CategoryPolicyAll
+	}
+
+	switch channel.CategoryPolicy {
+	case CategoryPolicyAll, CategoryPolicyFirstByRuleOrder, CategoryPolicyHighestPriority, CategoryPolicyMajorityVote:
+		return channel.CategoryPolicy
+	}
+	return CategoryPolicyAll
+}
+
+// validateCategoryPolicy checks the category policy of the channel.
+func validateCategoryPolicy(channel ChannelConfig) error {
+	switch channel.CategoryPolicy {
+	case "", CategoryPolicyAll, CategoryPolicyFirstByRuleOrder, CategoryPolicyHighestPriority, CategoryPolicyMajorityVote:
+		return nil
+	}
+	return fmt.Errorf("unknown category policy %q for channel %s", channel.CategoryPolicy, channel.ID)
+}
+
+// GetChannelRules returns the rules of the channel, including the inherited ones.
+func GetChannelRules(config *Config, channelID string) []RuleConfig {
+	config.cacheMu.RLock()
+	defer config.cacheMu.RUnlock()
+	return config.RulesCache[channelID]
+}
+
+// GetChannelCategories returns the categories of the channel rules, in rule order.
+func GetChannelCategories(config *Config, channelID string) []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, rule := range GetChannelRules(config, channelID) {
+		if !seen[rule.Category] {
+			seen[rule.Category] = true
+			categories = append(categories, rule.Category)
+		}
+	}
+	return categories
+}
+
+// GetReactionForCategory returns the reaction of the first reaction rule of
+// the category, normalized the way Slack expects emoji names.
+func GetReactionForCategory(config *Config, channelID, category string) (string, bool) {
+	for _, rule := range GetChannelRules(config, channelID) {
+		if reaction := NormalizeReactionName(rule.Reaction); rule.Category == category && reaction != "" {
+			return reaction, true
+		}
+	}
+	return "", false
+}
+
+// GetCategoryTree returns the parent of every category of the channel that has one.
+func GetCategoryTree(config *Config, channelID string) map[string]string {
+	config.cacheMu.RLock()
+	defer config.cacheMu.RUnlock()
+	return config.CategoryTreeCache[channelID]
+}
+
 func GetCategoryForReaction(config *Config, channelID, reaction string) (string, bool) {
-	log.Printf("config reaction cache: %+v", config.ReactionCache)
+	reaction = ResolveReactionName(config, reaction)
+	config.cacheMu.RLock()
+	defer config.cacheMu.RUnlock()
 	if channelReactions, exists := config.ReactionCache[channelID]; exists {
 		category, found := channelReactions[reaction]
 		return category, found
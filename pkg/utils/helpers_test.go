@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChannelConfig(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C123456", Name: "Test Channel", CountThreadReplies: true},
+			{ID: "C654321", Name: "Another Channel"},
+		},
+	}
+
+	channel, ok := GetChannelConfig(config, "C123456")
+	assert.True(t, ok)
+	assert.Equal(t, "Test Channel", channel.Name)
+	assert.True(t, channel.CountThreadReplies)
+
+	_, ok = GetChannelConfig(config, "C000000")
+	assert.False(t, ok, "Unknown channel should not be found")
+}
+
+func TestGetControllingReaction(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C123456", BeaconReaction: ":eyes:"},
+		},
+	}
+
+	assert.Equal(t, "eyes", GetControllingReaction(config, "C123456"), "Beacon should be normalized")
+	assert.Equal(t, "", GetControllingReaction(config, "C000000"))
+}
+
+func TestGetBeaconMode(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C1", BeaconReaction: ":eyes:"},
+			{ID: "C2"},
+			{ID: "C3", BeaconReaction: "eyes", BeaconMode: BeaconModeAnyReaction},
+			{ID: "C4", BeaconMode: BeaconModeRequired},
+			{ID: "C5", BeaconReaction: "eyes", BeaconMode: "sometimes"},
+			{ID: "C6", BeaconMode: BeaconModeText},
+		},
+	}
+
+	assert.Equal(t, BeaconModeRequired, GetBeaconMode(config, "C1"), "Beacon should be required by default")
+	assert.Equal(t, BeaconModeDisabled, GetBeaconMode(config, "C2"), "Channels without a beacon count every message")
+	assert.Equal(t, BeaconModeAnyReaction, GetBeaconMode(config, "C3"))
+	assert.Equal(t, BeaconModeDisabled, GetBeaconMode(config, "C4"), "Required mode without a beacon can't match anything")
+	assert.Equal(t, BeaconModeRequired, GetBeaconMode(config, "C5"), "Unknown modes fall back to the default")
+	assert.Equal(t, BeaconModeText, GetBeaconMode(config, "C6"), "Text matches don't need a beacon")
+}
+
+func TestGetCategoryPolicy(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C1"},
+			{ID: "C2", CategoryPolicy: CategoryPolicyMajorityVote},
+			{ID: "C3", CategoryPolicy: "random"},
+		},
+	}
+
+	assert.Equal(t, CategoryPolicyAll, GetCategoryPolicy(config, "C1"), "Every category should count by default")
+	assert.Equal(t, CategoryPolicyMajorityVote, GetCategoryPolicy(config, "C2"))
+	assert.Equal(t, CategoryPolicyAll, GetCategoryPolicy(config, "C3"), "Unknown policies fall back to the default")
+	assert.Equal(t, CategoryPolicyAll, GetCategoryPolicy(config, "C4"))
+}
+
+func TestGetLifecycleReactions(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C1", AckReaction: ":eyes:", ResolvedReaction: ":white_check_mark:"},
+			{ID: "C2"},
+		},
+	}
+
+	assert.Equal(t, "eyes", GetAckReaction(config, "C1"))
+	assert.Equal(t, "white_check_mark", GetResolvedReaction(config, "C1"))
+	assert.Equal(t, "", GetAckReaction(config, "C2"), "Lifecycle tracking should be off without reactions")
+	assert.Equal(t, "", GetResolvedReaction(config, "C3"))
+}
+
+func TestGetChannelCategories(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C123", Rules: []RuleConfig{
+				{Keywords: []string{"pipeline"}, Category: "CI/CD"},
+				{Reaction: ":bug:", Category: "Bug"},
+				{Reaction: ":cd:", Category: "CI/CD"},
+				{Reaction: "beetle", Category: "Bug"},
+			}},
+		},
+	}
+	assert.NoError(t, config.BuildReactionCache())
+
+	assert.Equal(t, []string{"CI/CD", "Bug"}, GetChannelCategories(config, "C123"))
+	assert.Empty(t, GetChannelCategories(config, "C999"))
+
+	reaction, ok := GetReactionForCategory(config, "C123", "CI/CD")
+	assert.True(t, ok, "Text rules should be skipped")
+	assert.Equal(t, "cd", reaction)
+	reaction, _ = GetReactionForCategory(config, "C123", "Bug")
+	assert.Equal(t, "bug", reaction, "The first reaction rule should win")
+	_, ok = GetReactionForCategory(config, "C123", "Unknown")
+	assert.False(t, ok)
+}
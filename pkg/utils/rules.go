@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RuleMatch is a category assigned to a message by one of the channel rules.
+type RuleMatch struct {
+	Category string
+	Priority int
+	Order    int // position of the rule in the channel rules
+}
+
+// TextRule is a compiled rule matching the message text.
+type TextRule struct {
+	RuleMatch
+	Pattern *regexp.Regexp
+}
+
+// compileTextRule compiles the keywords and the pattern of the rule into a
+// single regular expression. It reports false for rules without text matching.
+func compileTextRule(rule RuleConfig, order int) (TextRule, bool, error) {
+	var alternatives []string
+	var keywords []string
+	for _, keyword := range rule.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, regexp.QuoteMeta(keyword))
+		}
+	}
+	if len(keywords) > 0 {
+		// \b doesn't work for keywords starting or ending with symbols, like "c++"
+		alternatives = append(alternatives, `(?:^|\W)(?:`+strings.Join(keywords, "|")+`)(?:\W|$)`)
+	}
+	if rule.Pattern != "" {
+		alternatives = append(alternatives, "(?:"+rule.Pattern+")")
+	}
+	if len(alternatives) == 0 {
+		return TextRule{}, false, nil
+	}
+
+	pattern, err := regexp.Compile("(?i)" + strings.Join(alternatives, "|"))
+	if err != nil {
+		return TextRule{}, false, err
+	}
+	return TextRule{
+		RuleMatch: RuleMatch{Category: rule.Category, Priority: rule.Priority, Order: order},
+		Pattern:   pattern,
+	}, true, nil
+}
+
+// GetReactionRuleMatch returns the reaction rule of the channel matching the reaction.
+func GetReactionRuleMatch(config *Config, channelID, reaction string) (RuleMatch, bool) {
+	reaction = ResolveReactionName(config, reaction)
+	config.cacheMu.RLock()
+	defer config.cacheMu.RUnlock()
+	match, found := config.ReactionRuleCache[channelID][reaction]
+	return match, found
+}
+
+// GetTextRuleMatches returns the text rules of the channel matching the text.
+func GetTextRuleMatches(config *Config, channelID, text string) []RuleMatch {
+	if text == "" {
+		return nil
+	}
+	config.cacheMu.RLock()
+	rules := config.TextRuleCache[channelID]
+	config.cacheMu.RUnlock()
+
+	var matches []RuleMatch
+	for _, rule := range rules {
+		if rule.Pattern.MatchString(text) {
+			matches = append(matches, rule.RuleMatch)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRulesTestConfig(t *testing.T) *Config {
+	t.Helper()
+	config := &Config{
+		Channels: []ChannelConfig{
+			{
+				ID: "C123456",
+				Rules: []RuleConfig{
+					{Reaction: "bug", Category: "Infra bug", Priority: 1},
+					{Pattern: "pipeline|jenkins", Category: "CI/CD"},
+					{Keywords: []string{"disk", "c++"}, Category: "Capacity", Priority: -1},
+				},
+			},
+		},
+	}
+	assert.NoError(t, config.BuildReactionCache())
+	return config
+}
+
+func TestBuildReactionCache_TextRules(t *testing.T) {
+	config := newRulesTestConfig(t)
+
+	assert.Len(t, config.TextRuleCache["C123456"], 2, "Only rules with keywords or patterns are text rules")
+	assert.Equal(t, "Infra bug", config.ReactionCache["C123456"]["bug"])
+}
+
+func TestBuildReactionCache_InvalidPattern(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{ID: "C123456", Rules: []RuleConfig{{Pattern: "pipeline(", Category: "CI/CD"}}},
+		},
+	}
+
+	err := config.BuildReactionCache()
+	assert.Error(t, err, "Expected an error for an invalid pattern")
+	assert.Contains(t, err.Error(), "CI/CD")
+}
+
+func TestGetTextRuleMatches(t *testing.T) {
+	config := newRulesTestConfig(t)
+
+	matches := GetTextRuleMatches(config, "C123456", "The Jenkins pipeline is stuck")
+	assert.Equal(t, []RuleMatch{{Category: "CI/CD", Priority: 0, Order: 1}}, matches)
+
+	matches = GetTextRuleMatches(config, "C123456", "DISK is full")
+	assert.Equal(t, []RuleMatch{{Category: "Capacity", Priority: -1, Order: 2}}, matches)
+
+	matches = GetTextRuleMatches(config, "C123456", "my c++ build is slow")
+	assert.Equal(t, []RuleMatch{{Category: "Capacity", Priority: -1, Order: 2}}, matches)
+
+	// Keywords only match whole words
+	assert.Empty(t, GetTextRuleMatches(config, "C123456", "diskless nodes"))
+	assert.Empty(t, GetTextRuleMatches(config, "C999999", "pipeline"))
+}
+
+func TestGetReactionRuleMatch(t *testing.T) {
+	config := newRulesTestConfig(t)
+
+	match, ok := GetReactionRuleMatch(config, "C123456", "bug")
+	assert.True(t, ok)
+	assert.Equal(t, RuleMatch{Category: "Infra bug", Priority: 1, Order: 0}, match)
+
+	_, ok = GetReactionRuleMatch(config, "C123456", "tada")
+	assert.False(t, ok)
+}
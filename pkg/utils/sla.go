@@ -0,0 +1,47 @@
+package utils
+
+import "fmt"
+
+// GetSLATarget returns the SLA target of the category in the channel. A
+// target of the category itself wins over the ones of its ancestors, which
+// win over the target for every category.
+func GetSLATarget(config *Config, channelID, category string) (SLATarget, bool) {
+	channel, ok := GetChannelConfig(config, channelID)
+	if !ok {
+		return SLATarget{}, false
+	}
+
+	parents := GetCategoryTree(config, channelID)
+	seen := make(map[string]bool)
+	for current := category; current != "" && !seen[current]; current = parents[current] {
+		seen[current] = true
+		for _, target := range channel.SLA.Targets {
+			if target.Category == current {
+				return target, true
+			}
+		}
+	}
+	for _, target := range channel.SLA.Targets {
+		if target.Category == "" {
+			return target, true
+		}
+	}
+	return SLATarget{}, false
+}
+
+// validateSLA makes sure every SLA target of the channel can fire: ack
+// targets need an ack reaction and resolution targets a resolved reaction.
+func validateSLA(channel ChannelConfig) error {
+	for _, target := range channel.SLA.Targets {
+		if target.AckWithin < 0 || target.ResolveWithin < 0 {
+			return fmt.Errorf("negative SLA target for category %q in channel %s", target.Category, channel.ID)
+		}
+		if target.AckWithin > 0 && NormalizeReactionName(channel.AckReaction) == "" {
+			return fmt.Errorf("SLA target for category %q in channel %s has ack_within but the channel has no ack_reaction", target.Category, channel.ID)
+		}
+		if target.ResolveWithin > 0 && NormalizeReactionName(channel.ResolvedReaction) == "" {
+			return fmt.Errorf("SLA target for category %q in channel %s has resolve_within but the channel has no resolved_reaction", target.Category, channel.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSLATarget(t *testing.T) {
+	config := &Config{
+		Channels: []ChannelConfig{
+			{
+				ID:               "C1",
+				AckReaction:      "raising_hand",
+				ResolvedReaction: "white_check_mark",
+				Rules: []RuleConfig{
+					{Reaction: "bug", Category: "Infra bug", Parent: "Infra"},
+					{Reaction: "chart_with_upwards_trend", Category: "Capacity", Parent: "Infra"},
+				},
+				SLA: SLAConfig{Targets: []SLATarget{
+					{AckWithin: 4 * time.Hour},
+					{Category: "Infra", AckWithin: time.Hour},
+					{Category: "Infra bug", AckWithin: 30 * time.Minute, ResolveWithin: 8 * time.Hour},
+				}},
+			},
+			{ID: "C2"},
+		},
+	}
+	assert.NoError(t, config.BuildReactionCache())
+
+	target, ok := GetSLATarget(config, "C1", "Infra bug")
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Minute, target.AckWithin, "The category target should win")
+
+	target, ok = GetSLATarget(config, "C1", "Capacity")
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, target.AckWithin, "Parent targets should apply to children")
+
+	target, ok = GetSLATarget(config, "C1", "CI/CD")
+	assert.True(t, ok)
+	assert.Equal(t, 4*time.Hour, target.AckWithin, "The catch-all target should apply to the rest")
+
+	_, ok = GetSLATarget(config, "C2", "Infra bug")
+	assert.False(t, ok)
+}
+
+func TestBuildReactionCache_SLA(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel ChannelConfig
+		valid   bool
+	}{
+		{
+			name: "targets with their reactions",
+			channel: ChannelConfig{ID: "C1", AckReaction: "raising_hand", ResolvedReaction: "white_check_mark",
+				SLA: SLAConfig{Targets: []SLATarget{{AckWithin: time.Hour, ResolveWithin: 4 * time.Hour}}}},
+			valid: true,
+		},
+		{
+			name:    "ack target without ack reaction",
+			channel: ChannelConfig{ID: "C1", SLA: SLAConfig{Targets: []SLATarget{{Category: "Infra", AckWithin: time.Hour}}}},
+		},
+		{
+			name: "resolution target without resolved reaction",
+			channel: ChannelConfig{ID: "C1", AckReaction: "raising_hand",
+				SLA: SLAConfig{Targets: []SLATarget{{ResolveWithin: time.Hour}}}},
+		},
+		{
+			name:    "negative target",
+			channel: ChannelConfig{ID: "C1", AckReaction: "raising_hand", SLA: SLAConfig{Targets: []SLATarget{{AckWithin: -time.Hour}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Channels: []ChannelConfig{tt.channel}}
+			err := config.BuildReactionCache()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
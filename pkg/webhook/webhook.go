@@ -0,0 +1,266 @@
+// Package webhook delivers signed JSON events to outgoing webhooks.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Delivery headers.
+const (
+	HeaderEvent     = "X-Tars-Event"     // event type
+	HeaderDelivery  = "X-Tars-Delivery"  // unique delivery ID, the same across retries
+	HeaderSignature = "X-Tars-Signature" // "sha256=" and the hex HMAC-SHA256 of the body
+)
+
+// Defaults of the dispatcher.
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = 2 * time.Second // doubled after every failed attempt
+	defaultTimeout     = 10 * time.Second
+	defaultWorkers     = 4   // deliveries made at the same time
+	defaultQueueSize   = 256 // deliveries waiting for a worker, more are dropped
+)
+
+// Endpoint is a webhook URL and the events it gets.
+type Endpoint struct {
+	URL    string
+	Events []string // event types, every event if empty
+	Secret string   // key of the body signature, unsigned if empty
+}
+
+// Wants reports whether the endpoint gets the event.
+func (e Endpoint) Wants(event string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, event)
+}
+
+// Envelope is the JSON body of a delivery.
+type Envelope struct {
+	ID    string    `json:"id"`
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Failure is a delivery that failed after all attempts.
+type Failure struct {
+	Endpoint   Endpoint
+	DeliveryID string
+	Event      string
+	Payload    []byte
+	Attempts   int
+	Err        error
+}
+
+// delivery is an event queued for an endpoint.
+type delivery struct {
+	ctx      context.Context
+	endpoint Endpoint
+	id       string
+	event    string
+	payload  []byte
+}
+
+// Dispatcher delivers events to the endpoints that want them, in the
+// background. Deliveries are queued for a fixed set of workers.
+type Dispatcher struct {
+	endpoints   []Endpoint
+	client      *http.Client
+	onFailure   func(Failure)
+	mu          sync.Mutex // guards closed and sending on queue
+	closed      bool
+	queue       chan delivery
+	wg          sync.WaitGroup
+	MaxAttempts int           // attempts per delivery
+	Backoff     time.Duration // wait before the first retry
+}
+
+// NewDispatcher creates a dispatcher for the endpoints and starts its
+// workers, Close stops them. onFailure is called for every delivery that
+// failed after all attempts or was dropped, it may be nil.
+func NewDispatcher(endpoints []Endpoint, onFailure func(Failure)) *Dispatcher {
+	return newDispatcher(endpoints, onFailure, defaultWorkers, defaultQueueSize)
+}
+
+func newDispatcher(endpoints []Endpoint, onFailure func(Failure), workers, queueSize int) *Dispatcher {
+	d := &Dispatcher{
+		endpoints:   endpoints,
+		client:      &http.Client{Timeout: defaultTimeout},
+		onFailure:   onFailure,
+		queue:       make(chan delivery, queueSize),
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+	for i := 0; i < workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Dispatch queues the event for every endpoint that wants it without waiting
+// for the deliveries. Deliveries that don't fit in the queue, or come after
+// Close, are reported as failed instead of blocking the caller.
+func (d *Dispatcher) Dispatch(ctx context.Context, event string, data any) {
+	id, err := newDeliveryID()
+	if err != nil {
+		log.Printf("Failed to create webhook delivery ID: %v", err)
+		return
+	}
+	payload, err := json.Marshal(Envelope{ID: id, Event: event, Time: time.Now().UTC(), Data: data})
+	if err != nil {
+		log.Printf("Failed to encode webhook event %s: %v", event, err)
+		return
+	}
+
+	for _, endpoint := range d.endpoints {
+		if !endpoint.Wants(event) {
+			continue
+		}
+		job := delivery{ctx: ctx, endpoint: endpoint, id: id, event: event, payload: payload}
+		if err := d.enqueue(job); err != nil {
+			d.fail(job, 0, err)
+		}
+	}
+}
+
+// enqueue hands the delivery to the workers if there is room for it.
+func (d *Dispatcher) enqueue(job delivery) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return fmt.Errorf("not delivered: dispatcher closed")
+	}
+	select {
+	case d.queue <- job:
+		return nil
+	default:
+		return fmt.Errorf("not delivered: queue full")
+	}
+}
+
+// Close stops accepting events and blocks until the queued deliveries are
+// done. Deliveries whose context is done give up and are reported as failed.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// work makes the queued deliveries until the queue is closed.
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for job := range d.queue {
+		attempts, err := d.deliver(job.ctx, job.endpoint, job.id, job.event, job.payload)
+		if err != nil {
+			d.fail(job, attempts, err)
+		}
+	}
+}
+
+// fail reports a delivery that won't be made.
+func (d *Dispatcher) fail(job delivery, attempts int, err error) {
+	log.Printf("Failed to deliver webhook event %s to %s after %d attempts: %v", job.event, job.endpoint.URL, attempts, err)
+	if d.onFailure == nil {
+		return
+	}
+	d.onFailure(Failure{
+		Endpoint:   job.endpoint,
+		DeliveryID: job.id,
+		Event:      job.event,
+		Payload:    job.payload,
+		Attempts:   attempts,
+		Err:        err,
+	})
+}
+
+// deliver posts the payload to the endpoint, retrying with exponential
+// backoff on network errors, rate limits and server errors. Once the context
+// is done it gives up, so the delivery is reported as failed instead of lost.
+// It returns the number of attempts made.
+func (d *Dispatcher) deliver(ctx context.Context, endpoint Endpoint, id, event string, payload []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, fmt.Errorf("not delivered: %w", err)
+	}
+	backoff := d.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		retryable, err = d.post(ctx, endpoint, id, event, payload)
+		if err == nil {
+			return attempt, nil
+		}
+		if !retryable || attempt >= d.MaxAttempts {
+			return attempt, err
+		}
+
+		if ctx.Err() != nil {
+			return attempt, fmt.Errorf("%w (gave up: %w)", err, ctx.Err())
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("%w (gave up: %w)", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes a single delivery attempt. It reports whether a failure is worth retrying.
+func (d *Dispatcher) post(ctx context.Context, endpoint Endpoint, id, event string, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, id)
+	if endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(endpoint.Secret, payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// Sign returns the signature header value of the payload, which receivers
+// can recompute with the shared secret to verify the delivery.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatch(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher([]Endpoint{
+		{URL: server.URL, Events: []string{"request.resolved"}, Secret: "secret"},
+	}, nil)
+	dispatcher.Dispatch(context.Background(), "request.categorized", nil)
+	dispatcher.Dispatch(context.Background(), "request.resolved", map[string]string{"channel": "C123"})
+	dispatcher.Close()
+
+	r, body := <-received, <-bodies
+	assert.Empty(t, received, "Filtered events should not be delivered")
+	assert.Equal(t, "request.resolved", r.Header.Get(HeaderEvent))
+	assert.NotEmpty(t, r.Header.Get(HeaderDelivery))
+	assert.Equal(t, Sign("secret", body), r.Header.Get(HeaderSignature))
+
+	var envelope struct {
+		ID    string            `json:"id"`
+		Event string            `json:"event"`
+		Data  map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, r.Header.Get(HeaderDelivery), envelope.ID)
+	assert.Equal(t, "request.resolved", envelope.Event)
+	assert.Equal(t, "C123", envelope.Data["channel"])
+}
+
+func TestDispatch_Retry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var failures []Failure
+	dispatcher := NewDispatcher([]Endpoint{{URL: server.URL}}, func(f Failure) { failures = append(failures, f) })
+	dispatcher.Backoff = time.Millisecond
+	dispatcher.Dispatch(context.Background(), "sla.breached", nil)
+	dispatcher.Close()
+
+	assert.Equal(t, int32(3), calls.Load(), "Server errors should be retried")
+	assert.Empty(t, failures)
+}
+
+func TestDispatch_Failure(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	failures := make(map[string]Failure)
+	dispatcher := NewDispatcher([]Endpoint{
+		{URL: server.URL + "/down"},
+		{URL: server.URL + "/gone"},
+	}, func(f Failure) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures[f.Endpoint.URL] = f
+	})
+	dispatcher.MaxAttempts = 3
+	dispatcher.Backoff = time.Millisecond
+	dispatcher.Dispatch(context.Background(), "request.acked", nil)
+	dispatcher.Close()
+
+	assert.Len(t, failures, 2)
+	down := failures[server.URL+"/down"]
+	assert.Equal(t, 3, down.Attempts)
+	assert.Equal(t, "request.acked", down.Event)
+	assert.ErrorContains(t, down.Err, "500")
+	assert.NotEmpty(t, down.Payload)
+	assert.Equal(t, 1, failures[server.URL+"/gone"].Attempts, "Client errors should not be retried")
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestDispatch_Canceled(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	var mu sync.Mutex
+	var failures []Failure
+	dispatcher := NewDispatcher([]Endpoint{{URL: server.URL}}, func(f Failure) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, f)
+	})
+	dispatcher.Backoff = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcher.Dispatch(ctx, "request.resolved", nil)
+	<-started
+	cancel()
+	// events emitted after the shutdown are recorded as well
+	dispatcher.Dispatch(ctx, "request.reopened", nil)
+	dispatcher.Close()
+
+	assert.Len(t, failures, 2, "Deliveries cut short by the shutdown should be reported")
+	for _, failure := range failures {
+		assert.ErrorIs(t, failure.Err, context.Canceled)
+		assert.NotEmpty(t, failure.Payload)
+	}
+}
+
+func TestDispatch_Bounded(t *testing.T) {
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var failures []Failure
+	dispatcher := newDispatcher([]Endpoint{{URL: server.URL}}, func(f Failure) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, f)
+	}, 1, 1)
+	dispatcher.Dispatch(context.Background(), "request.acked", nil)
+	<-started
+	// the worker is busy, the first event waits in the queue and the second one doesn't fit
+	dispatcher.Dispatch(context.Background(), "request.resolved", nil)
+	dispatcher.Dispatch(context.Background(), "request.reopened", nil)
+	mu.Lock()
+	assert.Len(t, failures, 1, "Events that don't fit in the queue should be reported")
+	mu.Unlock()
+
+	close(release)
+	dispatcher.Close()
+	// events after Close are reported as well
+	dispatcher.Dispatch(context.Background(), "request.categorized", nil)
+
+	assert.Equal(t, int32(2), calls.Load(), "Queued events should be delivered before Close returns")
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "request.reopened", failures[0].Event)
+	assert.ErrorContains(t, failures[0].Err, "queue full")
+	assert.Equal(t, "request.categorized", failures[1].Event)
+	assert.ErrorContains(t, failures[1].Err, "closed")
+	assert.NotEmpty(t, failures[1].Payload)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"event":"test"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=8419ab361b37d61b696d008ef7549a18325132dae5da84c7424e8e1c590d0498", Sign("secret", []byte(`{"event":"test"}`)))
+	assert.NotEqual(t, Sign("secret", []byte("a")), Sign("other", []byte("a")))
+}